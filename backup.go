@@ -2,6 +2,7 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "backup_extension.h"
 import "C"
 import (
 	"errors"
@@ -47,6 +48,113 @@ func (b *BackupEngineInfo) Destroy() {
 	b.c = nil
 }
 
+// BackupInfo describes a backup.
+type BackupInfo struct {
+	ID        uint32
+	Timestamp int64
+	Size      uint64
+	NumFiles  uint32
+	// AppMetadata is the metadata passed to CreateNewBackupWithMetadata.
+	AppMetadata string
+}
+
+// ShareFilesNaming describes how the shared table files are named
+// with SetShareFilesWithChecksum(true).
+type ShareFilesNaming uint32
+
+// Share files namings, ShareFilesNamingFlagIncludeFileSize can be combined
+// with the others.
+const (
+	// ShareFilesNamingLegacyCrc32cAndFileSize uses the crc32c checksum and the
+	// file size, like <file_number>_<crc32c>_<file_size>.sst.
+	ShareFilesNamingLegacyCrc32cAndFileSize = ShareFilesNaming(1)
+	// ShareFilesNamingUseDbSessionID uses the db session id,
+	// like <file_number>_s<db_session_id>.sst.
+	ShareFilesNamingUseDbSessionID = ShareFilesNaming(2)
+	// ShareFilesNamingFlagIncludeFileSize adds the file size to the name.
+	ShareFilesNamingFlagIncludeFileSize = ShareFilesNaming(1 << 31)
+)
+
+// BackupEngineOptions captures the options of a backup engine.
+type BackupEngineOptions struct {
+	c *C.rocksdb_backup_engine_options_t
+}
+
+// NewBackupEngineOptions creates BackupEngineOptions for the backups in backupDir.
+func NewBackupEngineOptions(backupDir string) *BackupEngineOptions {
+	cBackupDir := C.CString(backupDir)
+	defer C.free(unsafe.Pointer(cBackupDir))
+	return &BackupEngineOptions{
+		c: C.rocksdb_backup_engine_options_create(cBackupDir),
+	}
+}
+
+// SetShareTableFiles specifies whether the table files are shared between
+// backups, so only new files are copied.
+// Default: true
+func (opts *BackupEngineOptions) SetShareTableFiles(value bool) {
+	C.rocksdb_backup_engine_options_set_share_table_files(opts.c, boolToChar(value))
+}
+
+// SetShareFilesWithChecksum specifies whether the shared table files are
+// identified by their checksum and size instead of their file number only,
+// which allows sharing between backups of different DBs.
+// Default: true
+func (opts *BackupEngineOptions) SetShareFilesWithChecksum(value bool) {
+	C.gorocksdb_backup_engine_options_set_share_files_with_checksum(opts.c, boolToChar(value))
+}
+
+// SetShareFilesWithChecksumNaming sets the naming of the shared table files.
+// Default: ShareFilesNamingUseDbSessionID | ShareFilesNamingFlagIncludeFileSize
+func (opts *BackupEngineOptions) SetShareFilesWithChecksumNaming(value ShareFilesNaming) {
+	C.rocksdb_backup_engine_options_set_share_files_with_checksum_naming(opts.c, C.int(int32(value)))
+}
+
+// SetSync specifies whether the backup files are synced, so a backup
+// is consistent after a machine crash.
+// Default: true
+func (opts *BackupEngineOptions) SetSync(value bool) {
+	C.rocksdb_backup_engine_options_set_sync(opts.c, boolToChar(value))
+}
+
+// SetDestroyOldData specifies whether all existing backups are deleted on open.
+// Default: false
+func (opts *BackupEngineOptions) SetDestroyOldData(value bool) {
+	C.rocksdb_backup_engine_options_set_destroy_old_data(opts.c, boolToChar(value))
+}
+
+// SetBackupLogFiles specifies whether the log files are backed up.
+// If false, the DB should be flushed before a backup.
+// Default: true
+func (opts *BackupEngineOptions) SetBackupLogFiles(value bool) {
+	C.rocksdb_backup_engine_options_set_backup_log_files(opts.c, boolToChar(value))
+}
+
+// SetBackupRateLimit limits the bytes per second written during a backup.
+// Default: 0, unlimited
+func (opts *BackupEngineOptions) SetBackupRateLimit(value uint64) {
+	C.rocksdb_backup_engine_options_set_backup_rate_limit(opts.c, C.uint64_t(value))
+}
+
+// SetRestoreRateLimit limits the bytes per second written during a restore.
+// Default: 0, unlimited
+func (opts *BackupEngineOptions) SetRestoreRateLimit(value uint64) {
+	C.rocksdb_backup_engine_options_set_restore_rate_limit(opts.c, C.uint64_t(value))
+}
+
+// SetMaxBackgroundOperations sets the number of threads copying files
+// during backup and restore.
+// Default: 1
+func (opts *BackupEngineOptions) SetMaxBackgroundOperations(value int) {
+	C.rocksdb_backup_engine_options_set_max_background_operations(opts.c, C.int(value))
+}
+
+// Destroy destroys this BackupEngineOptions instance.
+func (opts *BackupEngineOptions) Destroy() {
+	C.rocksdb_backup_engine_options_destroy(opts.c)
+	opts.c = nil
+}
+
 // RestoreOptions captures the options to be used during
 // restoration of a backup.
 type RestoreOptions struct {
@@ -73,6 +181,14 @@ func (ro *RestoreOptions) Destroy() {
 	C.rocksdb_restore_options_destroy(ro.c)
 }
 
+// BackupableDB is an open database which can be backed up by a BackupEngine,
+// these are *DB (also opened with TTL or column families) and *TransactionDB.
+// All column families of the database are backed up and restored.
+type BackupableDB interface {
+	// baseDB returns the handle of the database as plain db.
+	baseDB() *C.rocksdb_t
+}
+
 // BackupEngine is a reusable handle to a RocksDB Backup, created by
 // OpenBackupEngine.
 type BackupEngine struct {
@@ -99,24 +215,144 @@ func OpenBackupEngine(opts *Options, path string) (*BackupEngine, error) {
 	}, nil
 }
 
+// OpenBackupEngineWithOptions opens a backup engine with the BackupEngineOptions.
+// The env of opts is used.
+func OpenBackupEngineWithOptions(opts *Options, beOpts *BackupEngineOptions) (*BackupEngine, error) {
+	var cErr *C.char
+	env := opts.env
+	if env == nil {
+		env = NewDefaultEnv()
+		defer env.Destroy()
+	}
+	be := C.rocksdb_backup_engine_open_opts(beOpts.c, env.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	return &BackupEngine{
+		c:    be,
+		opts: opts,
+	}, nil
+}
+
 // UnsafeGetBackupEngine returns the underlying c backup engine.
 func (b *BackupEngine) UnsafeGetBackupEngine() unsafe.Pointer {
 	return unsafe.Pointer(b.c)
 }
 
 // CreateNewBackup takes a new backup from db.
-func (b *BackupEngine) CreateNewBackup(db *DB) error {
+func (b *BackupEngine) CreateNewBackup(db BackupableDB) error {
+	var cErr *C.char
+
+	C.rocksdb_backup_engine_create_new_backup(b.c, db.baseDB(), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+
+	return nil
+}
+
+// CreateNewBackupFlush takes a new backup from db.
+// If flushBeforeBackup is true, the memtables are flushed before,
+// so the log files are not needed in the backup.
+func (b *BackupEngine) CreateNewBackupFlush(db BackupableDB, flushBeforeBackup bool) error {
+	var cErr *C.char
+	C.rocksdb_backup_engine_create_new_backup_flush(b.c, db.baseDB(), boolToChar(flushBeforeBackup), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// CreateNewBackupWithMetadata takes a new backup from db and stores
+// the application specific metadata with it, see BackupInfo.
+// If flushBeforeBackup is true, the memtables are flushed before.
+func (b *BackupEngine) CreateNewBackupWithMetadata(db BackupableDB, metadata string, flushBeforeBackup bool) error {
 	var cErr *C.char
+	cMetadata := []byte(metadata)
+	C.gorocksdb_backup_engine_create_new_backup_with_metadata(
+		b.c, db.baseDB(), byteToChar(cMetadata), C.size_t(len(cMetadata)), boolToChar(flushBeforeBackup), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
 
-	C.rocksdb_backup_engine_create_new_backup(b.c, db.c, &cErr)
+// PurgeOldBackups deletes all backups but the newest numBackupsToKeep.
+func (b *BackupEngine) PurgeOldBackups(numBackupsToKeep uint32) error {
+	var cErr *C.char
+	C.rocksdb_backup_engine_purge_old_backups(b.c, C.uint32_t(numBackupsToKeep), &cErr)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
 		return errors.New(C.GoString(cErr))
 	}
+	return nil
+}
 
+// DeleteBackup deletes the backup with id.
+func (b *BackupEngine) DeleteBackup(id uint32) error {
+	var cErr *C.char
+	C.gorocksdb_backup_engine_delete_backup(b.c, C.uint32_t(id), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
 	return nil
 }
 
+// VerifyBackup checks that all files of the backup with id exist
+// and have the expected sizes.
+func (b *BackupEngine) VerifyBackup(id uint32) error {
+	return b.verifyBackup(id, false)
+}
+
+// VerifyBackupWithChecksum is like VerifyBackup but also
+// verifies the checksums of the files, which reads all files.
+func (b *BackupEngine) VerifyBackupWithChecksum(id uint32) error {
+	return b.verifyBackup(id, true)
+}
+
+func (b *BackupEngine) verifyBackup(id uint32, verifyWithChecksum bool) error {
+	var cErr *C.char
+	if verifyWithChecksum {
+		C.gorocksdb_backup_engine_verify_backup_with_checksum(b.c, C.uint32_t(id), &cErr)
+	} else {
+		C.rocksdb_backup_engine_verify_backup(b.c, C.uint32_t(id), &cErr)
+	}
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetBackupInfo returns the information about the backups, ordered by id.
+func (b *BackupEngine) GetBackupInfo() []BackupInfo {
+	var cNum C.size_t
+	cInfos := C.gorocksdb_backup_engine_get_backup_infos(b.c, &cNum)
+	defer C.gorocksdb_backup_infos_destroy(cInfos, cNum)
+
+	num := int(cNum)
+	infos := make([]BackupInfo, num)
+	if num == 0 {
+		return infos
+	}
+	cInfoSlice := (*[1 << 30]C.gorocksdb_backup_info_t)(unsafe.Pointer(cInfos))[:num:num]
+	for i, cInfo := range cInfoSlice {
+		infos[i] = BackupInfo{
+			ID:          uint32(cInfo.backup_id),
+			Timestamp:   int64(cInfo.timestamp),
+			Size:        uint64(cInfo.size),
+			NumFiles:    uint32(cInfo.number_files),
+			AppMetadata: charToString(cInfo.app_metadata, cInfo.app_metadata_len),
+		}
+	}
+	return infos
+}
+
 // GetInfo gets an object that gives information about
 // the backups that have already been taken
 func (b *BackupEngine) GetInfo() *BackupEngineInfo {
@@ -144,6 +380,25 @@ func (b *BackupEngine) RestoreDBFromLatestBackup(dbDir, walDir string, ro *Resto
 	return nil
 }
 
+// RestoreDBFromBackup restores the backup with id to dbDir. walDir
+// is where the write ahead logs are restored to and usually the same as dbDir.
+func (b *BackupEngine) RestoreDBFromBackup(id uint32, dbDir, walDir string, ro *RestoreOptions) error {
+	var cErr *C.char
+	cDbDir := C.CString(dbDir)
+	cWalDir := C.CString(walDir)
+	defer func() {
+		C.free(unsafe.Pointer(cDbDir))
+		C.free(unsafe.Pointer(cWalDir))
+	}()
+
+	C.rocksdb_backup_engine_restore_db_from_backup(b.c, cDbDir, cWalDir, ro.c, C.uint32_t(id), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
 // Close close the backup engine and cleans up state
 // The backups already taken remain on storage.
 func (b *BackupEngine) Close() {
@@ -0,0 +1,63 @@
+#include "backup_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include <string>
+#include <vector>
+#include "rocksdb_internal.h"
+
+using rocksdb::BackupInfo;
+
+extern "C" {
+
+
+void gorocksdb_backup_engine_options_set_share_files_with_checksum(rocksdb_backup_engine_options_t* opts, unsigned char v) {
+	opts->rep.share_files_with_checksum = v;
+}
+
+void gorocksdb_backup_engine_create_new_backup_with_metadata(
+	rocksdb_backup_engine_t* be, rocksdb_t* db,
+	const char* app_metadata, size_t app_metadata_len,
+	unsigned char flush_before_backup, char** errptr) {
+
+	SaveError(errptr, be->rep->CreateNewBackupWithMetadata(
+		db->rep, std::string(app_metadata, app_metadata_len), flush_before_backup));
+}
+
+void gorocksdb_backup_engine_delete_backup(rocksdb_backup_engine_t* be, uint32_t backup_id, char** errptr) {
+	SaveError(errptr, be->rep->DeleteBackup(backup_id));
+}
+
+void gorocksdb_backup_engine_verify_backup_with_checksum(rocksdb_backup_engine_t* be, uint32_t backup_id, char** errptr) {
+	SaveError(errptr, be->rep->VerifyBackup(backup_id, true));
+}
+
+gorocksdb_backup_info_t* gorocksdb_backup_engine_get_backup_infos(rocksdb_backup_engine_t* be, size_t* num) {
+	std::vector<BackupInfo> infos;
+	be->rep->GetBackupInfo(&infos);
+
+	*num = infos.size();
+	gorocksdb_backup_info_t* result = static_cast<gorocksdb_backup_info_t*>(
+		malloc(sizeof(gorocksdb_backup_info_t) * infos.size()));
+	for (size_t i = 0; i < infos.size(); i++) {
+		const BackupInfo& info = infos[i];
+		result[i].backup_id = info.backup_id;
+		result[i].timestamp = info.timestamp;
+		result[i].size = info.size;
+		result[i].number_files = info.number_files;
+		result[i].app_metadata = static_cast<char*>(malloc(info.app_metadata.size()));
+		memcpy(result[i].app_metadata, info.app_metadata.data(), info.app_metadata.size());
+		result[i].app_metadata_len = info.app_metadata.size();
+	}
+	return result;
+}
+
+void gorocksdb_backup_infos_destroy(gorocksdb_backup_info_t* infos, size_t num) {
+	for (size_t i = 0; i < num; i++) {
+		free(infos[i].app_metadata);
+	}
+	free(infos);
+}
+
+
+}
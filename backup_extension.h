@@ -0,0 +1,39 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+typedef struct {
+	uint32_t backup_id;
+	int64_t timestamp;
+	uint64_t size;
+	uint32_t number_files;
+	char* app_metadata;
+	size_t app_metadata_len;
+} gorocksdb_backup_info_t;
+
+/* BackupEngineOptions */
+
+void gorocksdb_backup_engine_options_set_share_files_with_checksum(rocksdb_backup_engine_options_t* opts, unsigned char v);
+
+/* BackupEngine */
+
+void gorocksdb_backup_engine_create_new_backup_with_metadata(
+	rocksdb_backup_engine_t* be, rocksdb_t* db,
+	const char* app_metadata, size_t app_metadata_len,
+	unsigned char flush_before_backup, char** errptr);
+
+void gorocksdb_backup_engine_delete_backup(rocksdb_backup_engine_t* be, uint32_t backup_id, char** errptr);
+
+void gorocksdb_backup_engine_verify_backup_with_checksum(rocksdb_backup_engine_t* be, uint32_t backup_id, char** errptr);
+
+// The infos have to be freed with gorocksdb_backup_infos_destroy.
+gorocksdb_backup_info_t* gorocksdb_backup_engine_get_backup_infos(rocksdb_backup_engine_t* be, size_t* num);
+
+void gorocksdb_backup_infos_destroy(gorocksdb_backup_info_t* infos, size_t num);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -42,3 +42,141 @@ func TestBackup(t *testing.T) {
 
 	defer rso.Destroy()
 }
+
+func TestBackupEngineManagement(t *testing.T) {
+	backupDir, err := ioutil.TempDir("", "gorocksdb-TestBackupEngineManagement-backup")
+	require.NoError(t, err)
+	restoreDir, err := ioutil.TempDir("", "gorocksdb-TestBackupEngineManagement-restore")
+	require.NoError(t, err)
+
+	db := newTestDB(t, "TestBackupEngineManagement", nil)
+	defer db.Close()
+
+	beOpts := NewBackupEngineOptions(backupDir)
+	defer beOpts.Destroy()
+	beOpts.SetShareTableFiles(true)
+	beOpts.SetShareFilesWithChecksum(true)
+	beOpts.SetShareFilesWithChecksumNaming(ShareFilesNamingUseDbSessionID | ShareFilesNamingFlagIncludeFileSize)
+	beOpts.SetSync(false)
+	beOpts.SetBackupLogFiles(true)
+	beOpts.SetBackupRateLimit(0)
+	beOpts.SetRestoreRateLimit(0)
+	beOpts.SetMaxBackgroundOperations(2)
+
+	opts := NewDefaultOptions()
+	be, err := OpenBackupEngineWithOptions(opts, beOpts)
+	require.NoError(t, err)
+	defer be.Close()
+
+	wo := NewDefaultWriteOptions()
+	for i, value := range []string{"value1", "value2", "value3"} {
+		require.NoError(t, db.Put(wo, []byte("key"), []byte(value)))
+		if i == 0 {
+			require.NoError(t, be.CreateNewBackupFlush(db, true))
+		} else {
+			require.NoError(t, be.CreateNewBackupWithMetadata(db, "meta"+value, false))
+		}
+	}
+
+	infos := be.GetBackupInfo()
+	require.Len(t, infos, 3)
+	require.Equal(t, "", infos[0].AppMetadata)
+	require.Equal(t, "metavalue2", infos[1].AppMetadata)
+	require.Equal(t, "metavalue3", infos[2].AppMetadata)
+	for _, info := range infos {
+		require.NoError(t, be.VerifyBackup(info.ID))
+		require.NoError(t, be.VerifyBackupWithChecksum(info.ID))
+		require.True(t, info.NumFiles > 0)
+	}
+
+	require.NoError(t, be.DeleteBackup(infos[2].ID))
+	require.NoError(t, be.PurgeOldBackups(1))
+	remaining := be.GetBackupInfo()
+	require.Len(t, remaining, 1)
+	require.Equal(t, infos[1].ID, remaining[0].ID)
+	require.Error(t, be.VerifyBackup(infos[0].ID))
+
+	ro := NewRestoreOptions()
+	defer ro.Destroy()
+	require.NoError(t, be.RestoreDBFromBackup(remaining[0].ID, restoreDir, restoreDir, ro))
+
+	restoreOpts := NewDefaultOptions()
+	restored, err := OpenDb(restoreOpts, restoreDir)
+	require.NoError(t, err)
+	defer restored.Close()
+	v, err := restored.GetBytes(NewDefaultReadOptions(), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value2"), v)
+}
+
+func TestBackupTransactionDBColumnFamilies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestBackupTransactionDBColumnFamilies")
+	require.NoError(t, err)
+	backupDir, err := ioutil.TempDir("", "gorocksdb-TestBackupTransactionDBColumnFamilies-backup")
+	require.NoError(t, err)
+	restoreDir, err := ioutil.TempDir("", "gorocksdb-TestBackupTransactionDBColumnFamilies-restore")
+	require.NoError(t, err)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	opts.SetCreateIfMissingColumnFamilies(true)
+	transactionDBOpts := NewDefaultTransactionDBOptions()
+	cfNames := []string{"default", "other"}
+	cfOpts := []*Options{NewDefaultOptions(), NewDefaultOptions()}
+
+	db, cfs, err := OpenTransactionDbColumnFamilies(opts, transactionDBOpts, dir, cfNames, cfOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key"), []byte("default")))
+	require.NoError(t, db.PutCF(wo, cfs[1], []byte("key"), []byte("other")))
+
+	beOpts := NewBackupEngineOptions(backupDir)
+	defer beOpts.Destroy()
+	be, err := OpenBackupEngineWithOptions(opts, beOpts)
+	require.NoError(t, err)
+	defer be.Close()
+	require.NoError(t, be.CreateNewBackupFlush(db, true))
+
+	ro := NewRestoreOptions()
+	defer ro.Destroy()
+	require.NoError(t, be.RestoreDBFromLatestBackup(restoreDir, restoreDir, ro))
+
+	names, err := ListColumnFamilies(NewDefaultOptions(), restoreDir)
+	require.NoError(t, err)
+	require.ElementsMatch(t, cfNames, names)
+
+	restored, restoredCfs, err := OpenTransactionDbColumnFamilies(
+		NewDefaultOptions(), NewDefaultTransactionDBOptions(), restoreDir, cfNames, cfOpts)
+	require.NoError(t, err)
+	defer restored.Close()
+
+	rdo := NewDefaultReadOptions()
+	v, err := restored.Get(rdo, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("default"), v)
+	v, err = restored.GetCF(rdo, restoredCfs[1], []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("other"), v)
+}
+
+func TestBackupTTLDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestBackupTTLDB")
+	require.NoError(t, err)
+	backupDir, err := ioutil.TempDir("", "gorocksdb-TestBackupTTLDB-backup")
+	require.NoError(t, err)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	db, err := OpenDbWithTTL(opts, dir, 3600)
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Put(NewDefaultWriteOptions(), []byte("key"), []byte("value")))
+
+	be, err := OpenBackupEngine(opts, backupDir)
+	require.NoError(t, err)
+	defer be.Close()
+	require.NoError(t, be.CreateNewBackup(db))
+	require.Len(t, be.GetBackupInfo(), 1)
+}
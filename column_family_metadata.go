@@ -0,0 +1,102 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "column_family_metadata_extension.h"
+import "C"
+import "unsafe"
+
+// SstFileMetaData describes an sst file of a column family.
+type SstFileMetaData struct {
+	// Name is the name of the file in Directory.
+	Name           string
+	Directory      string
+	Size           uint64
+	SmallestKey    []byte
+	LargestKey     []byte
+	SmallestSeqno  uint64
+	LargestSeqno   uint64
+	NumEntries     uint64
+	NumDeletions   uint64
+	BeingCompacted bool
+}
+
+// LevelMetaData describes the sst files of a level.
+type LevelMetaData struct {
+	Level int
+	// Size is the total size of the files in bytes.
+	Size  uint64
+	Files []SstFileMetaData
+}
+
+// ColumnFamilyMetaData describes the sst files of a column family.
+type ColumnFamilyMetaData struct {
+	Name string
+	// Size is the total size of the files in bytes.
+	Size      uint64
+	FileCount uint64
+	Levels    []LevelMetaData
+}
+
+// GetColumnFamilyMetaData returns the metadata of the default column family.
+func (db *DB) GetColumnFamilyMetaData() *ColumnFamilyMetaData {
+	return db.getColumnFamilyMetaData(nil)
+}
+
+// GetColumnFamilyMetaDataCF returns the metadata of the column family cf.
+func (db *DB) GetColumnFamilyMetaDataCF(cf *ColumnFamilyHandle) *ColumnFamilyMetaData {
+	return db.getColumnFamilyMetaData(cf.c)
+}
+
+func (db *DB) getColumnFamilyMetaData(cf *C.rocksdb_column_family_handle_t) *ColumnFamilyMetaData {
+	var (
+		cName      *C.char
+		cNameLen   C.size_t
+		cSize      C.uint64_t
+		cFileCount C.uint64_t
+		cLevels    *C.gorocksdb_level_metadata_t
+		cNumLevels C.size_t
+	)
+	C.gorocksdb_get_column_family_metadata(db.c, cf,
+		&cName, &cNameLen, &cSize, &cFileCount, &cLevels, &cNumLevels)
+	defer C.gorocksdb_column_family_metadata_destroy(cName, cLevels, cNumLevels)
+
+	numLevels := int(cNumLevels)
+	metadata := &ColumnFamilyMetaData{
+		Name:      charToString(cName, cNameLen),
+		Size:      uint64(cSize),
+		FileCount: uint64(cFileCount),
+		Levels:    make([]LevelMetaData, numLevels),
+	}
+	if numLevels == 0 {
+		return metadata
+	}
+	cLevelSlice := (*[1 << 30]C.gorocksdb_level_metadata_t)(unsafe.Pointer(cLevels))[:numLevels:numLevels]
+	for i, cLevel := range cLevelSlice {
+		numFiles := int(cLevel.num_files)
+		level := LevelMetaData{
+			Level: int(cLevel.level),
+			Size:  uint64(cLevel.size),
+			Files: make([]SstFileMetaData, numFiles),
+		}
+		if numFiles > 0 {
+			cFileSlice := (*[1 << 30]C.gorocksdb_sst_file_metadata_t)(unsafe.Pointer(cLevel.files))[:numFiles:numFiles]
+			for j, cFile := range cFileSlice {
+				level.Files[j] = SstFileMetaData{
+					Name:           charToString(cFile.name, cFile.name_len),
+					Directory:      charToString(cFile.directory, cFile.directory_len),
+					Size:           uint64(cFile.size),
+					SmallestKey:    C.GoBytes(unsafe.Pointer(cFile.smallest_key), C.int(cFile.smallest_key_len)),
+					LargestKey:     C.GoBytes(unsafe.Pointer(cFile.largest_key), C.int(cFile.largest_key_len)),
+					SmallestSeqno:  uint64(cFile.smallest_seqno),
+					LargestSeqno:   uint64(cFile.largest_seqno),
+					NumEntries:     uint64(cFile.num_entries),
+					NumDeletions:   uint64(cFile.num_deletions),
+					BeingCompacted: cFile.being_compacted != 0,
+				}
+			}
+		}
+		metadata.Levels[i] = level
+	}
+	return metadata
+}
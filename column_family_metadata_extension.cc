@@ -0,0 +1,80 @@
+#include "column_family_metadata_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include <string>
+#include "rocksdb/metadata.h"
+#include "rocksdb_internal.h"
+
+using rocksdb::ColumnFamilyMetaData;
+using rocksdb::LevelMetaData;
+using rocksdb::SstFileMetaData;
+
+static void copy_string(const std::string& s, char** c, size_t* len) {
+	*c = static_cast<char*>(malloc(s.size()));
+	memcpy(*c, s.data(), s.size());
+	*len = s.size();
+}
+
+extern "C" {
+
+
+void gorocksdb_get_column_family_metadata(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	char** name, size_t* name_len, uint64_t* size, uint64_t* file_count,
+	gorocksdb_level_metadata_t** levels, size_t* num_levels) {
+
+	ColumnFamilyMetaData metadata;
+	if (column_family == NULL) {
+		db->rep->GetColumnFamilyMetaData(&metadata);
+	} else {
+		db->rep->GetColumnFamilyMetaData(column_family->rep, &metadata);
+	}
+
+	copy_string(metadata.name, name, name_len);
+	*size = metadata.size;
+	*file_count = metadata.file_count;
+	*num_levels = metadata.levels.size();
+	*levels = static_cast<gorocksdb_level_metadata_t*>(calloc(metadata.levels.size(), sizeof(gorocksdb_level_metadata_t)));
+	for (size_t i = 0; i < metadata.levels.size(); i++) {
+		const LevelMetaData& src_level = metadata.levels[i];
+		gorocksdb_level_metadata_t* dst_level = &(*levels)[i];
+		dst_level->level = src_level.level;
+		dst_level->size = src_level.size;
+		dst_level->num_files = src_level.files.size();
+		dst_level->files = static_cast<gorocksdb_sst_file_metadata_t*>(
+			calloc(src_level.files.size(), sizeof(gorocksdb_sst_file_metadata_t)));
+		for (size_t j = 0; j < src_level.files.size(); j++) {
+			const SstFileMetaData& src = src_level.files[j];
+			gorocksdb_sst_file_metadata_t* dst = &dst_level->files[j];
+			copy_string(src.relative_filename, &dst->name, &dst->name_len);
+			copy_string(src.directory, &dst->directory, &dst->directory_len);
+			dst->size = src.size;
+			copy_string(src.smallestkey, &dst->smallest_key, &dst->smallest_key_len);
+			copy_string(src.largestkey, &dst->largest_key, &dst->largest_key_len);
+			dst->smallest_seqno = src.smallest_seqno;
+			dst->largest_seqno = src.largest_seqno;
+			dst->num_entries = src.num_entries;
+			dst->num_deletions = src.num_deletions;
+			dst->being_compacted = src.being_compacted;
+		}
+	}
+}
+
+void gorocksdb_column_family_metadata_destroy(char* name, gorocksdb_level_metadata_t* levels, size_t num_levels) {
+	free(name);
+	for (size_t i = 0; i < num_levels; i++) {
+		for (size_t j = 0; j < levels[i].num_files; j++) {
+			gorocksdb_sst_file_metadata_t* file = &levels[i].files[j];
+			free(file->name);
+			free(file->directory);
+			free(file->smallest_key);
+			free(file->largest_key);
+		}
+		free(levels[i].files);
+	}
+	free(levels);
+}
+
+
+}
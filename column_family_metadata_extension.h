@@ -0,0 +1,43 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+typedef struct {
+	char* name;
+	size_t name_len;
+	char* directory;
+	size_t directory_len;
+	uint64_t size;
+	char* smallest_key;
+	size_t smallest_key_len;
+	char* largest_key;
+	size_t largest_key_len;
+	uint64_t smallest_seqno;
+	uint64_t largest_seqno;
+	uint64_t num_entries;
+	uint64_t num_deletions;
+	unsigned char being_compacted;
+} gorocksdb_sst_file_metadata_t;
+
+typedef struct {
+	int level;
+	uint64_t size;
+	gorocksdb_sst_file_metadata_t* files;
+	size_t num_files;
+} gorocksdb_level_metadata_t;
+
+// column_family may be NULL for the default column family.
+// The name and the levels have to be freed with gorocksdb_column_family_metadata_destroy.
+void gorocksdb_get_column_family_metadata(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	char** name, size_t* name_len, uint64_t* size, uint64_t* file_count,
+	gorocksdb_level_metadata_t** levels, size_t* num_levels);
+
+void gorocksdb_column_family_metadata_destroy(char* name, gorocksdb_level_metadata_t* levels, size_t num_levels);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
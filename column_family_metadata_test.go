@@ -0,0 +1,57 @@
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnFamilyMetaData(t *testing.T) {
+	db, cfs := newTestDBCFs(t, "TestColumnFamilyMetaData", []string{"default", "other"}, nil)
+	defer db.Close()
+	defer cfs[1].Destroy()
+	defer cfs[0].Destroy()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key1"), []byte("val1")))
+	require.NoError(t, db.Put(wo, []byte("key2"), []byte("val2")))
+	require.NoError(t, db.Delete(wo, []byte("key3")))
+	require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	require.NoError(t, db.PutCF(wo, cfs[1], []byte("other"), []byte("val")))
+
+	metadata := db.GetColumnFamilyMetaData()
+	require.Equal(t, "default", metadata.Name)
+	require.Equal(t, uint64(1), metadata.FileCount)
+	require.NotEmpty(t, metadata.Levels)
+	require.Equal(t, 0, metadata.Levels[0].Level)
+	require.Len(t, metadata.Levels[0].Files, 1)
+	file := metadata.Levels[0].Files[0]
+	require.Equal(t, metadata.Size, file.Size)
+	require.Equal(t, metadata.Levels[0].Size, file.Size)
+	require.Equal(t, []byte("key1"), file.SmallestKey)
+	require.Equal(t, []byte("key3"), file.LargestKey)
+	require.Equal(t, uint64(3), file.NumEntries)
+	require.Equal(t, uint64(1), file.NumDeletions)
+	require.True(t, file.SmallestSeqno <= file.LargestSeqno)
+	require.False(t, file.BeingCompacted)
+
+	// the memtable of other is not flushed.
+	metadata = db.GetColumnFamilyMetaDataCF(cfs[1])
+	require.Equal(t, "other", metadata.Name)
+	require.Equal(t, uint64(0), metadata.FileCount)
+
+	db.CompactRangeCF(cfs[1], Range{nil, nil})
+	metadata = db.GetColumnFamilyMetaDataCF(cfs[1])
+	require.Equal(t, uint64(1), metadata.FileCount)
+
+	liveFiles := db.GetLiveFilesMetaData()
+	require.Len(t, liveFiles, 2)
+	byCF := make(map[string]LiveFileMetadata)
+	for _, liveFile := range liveFiles {
+		byCF[liveFile.ColumnFamilyName] = liveFile
+	}
+	require.Equal(t, uint64(3), byCF["default"].NumEntries)
+	require.Equal(t, uint64(1), byCF["default"].NumDeletions)
+	require.Equal(t, uint64(1), byCF["other"].NumEntries)
+	require.Equal(t, uint64(0), byCF["other"].NumDeletions)
+}
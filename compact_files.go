@@ -0,0 +1,97 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "compact_files_extension.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// CompactionOptions represent all of the available options for CompactFiles.
+type CompactionOptions struct {
+	c *C.gorocksdb_compactionoptions_t
+}
+
+// NewDefaultCompactionOptions creates a default CompactionOptions object.
+func NewDefaultCompactionOptions() *CompactionOptions {
+	return &CompactionOptions{c: C.gorocksdb_compactionoptions_create()}
+}
+
+// SetCompression sets the compression of the output files.
+// Default: SnappyCompression
+func (opts *CompactionOptions) SetCompression(value CompressionType) {
+	C.gorocksdb_compactionoptions_set_compression(opts.c, C.int(value))
+}
+
+// SetOutputFileSizeLimit sets the maximum size of an output file in bytes.
+// Default: no limit
+func (opts *CompactionOptions) SetOutputFileSizeLimit(value uint64) {
+	C.gorocksdb_compactionoptions_set_output_file_size_limit(opts.c, C.uint64_t(value))
+}
+
+// SetMaxSubcompactions sets the maximum number of threads of the compaction,
+// 0 means the max_subcompactions of the DB options.
+// Default: 0
+func (opts *CompactionOptions) SetMaxSubcompactions(value uint32) {
+	C.gorocksdb_compactionoptions_set_max_subcompactions(opts.c, C.uint32_t(value))
+}
+
+// Destroy deallocates the CompactionOptions object.
+func (opts *CompactionOptions) Destroy() {
+	C.gorocksdb_compactionoptions_destroy(opts.c)
+	opts.c = nil
+}
+
+// CompactFiles compacts the files inputFiles of the default column family
+// into outputLevel and returns the paths of the output files.
+// The names of the input files are the names returned by
+// GetLiveFilesMetaData or GetColumnFamilyMetaData.
+// It fails if one of the files is being compacted.
+func (db *DB) CompactFiles(opts *CompactionOptions, inputFiles []string, outputLevel int) ([]string, error) {
+	return db.compactFiles(nil, opts, inputFiles, outputLevel)
+}
+
+// CompactFilesCF compacts the files inputFiles of the column family cf
+// into outputLevel and returns the paths of the output files.
+// See CompactFiles.
+func (db *DB) CompactFilesCF(cf *ColumnFamilyHandle, opts *CompactionOptions, inputFiles []string, outputLevel int) ([]string, error) {
+	return db.compactFiles(cf.c, opts, inputFiles, outputLevel)
+}
+
+func (db *DB) compactFiles(
+	cf *C.rocksdb_column_family_handle_t,
+	opts *CompactionOptions,
+	inputFiles []string,
+	outputLevel int,
+) ([]string, error) {
+	if len(inputFiles) == 0 {
+		return nil, errors.New("no files to compact")
+	}
+	cInputFiles := make([]*C.char, len(inputFiles))
+	for i, s := range inputFiles {
+		cInputFiles[i] = C.CString(s)
+	}
+	defer func() {
+		for _, s := range cInputFiles {
+			C.free(unsafe.Pointer(s))
+		}
+	}()
+
+	var (
+		cErr             *C.char
+		cOutputFiles     **C.char
+		cOutputFilesLens *C.size_t
+		cNumOutputFiles  C.size_t
+	)
+	C.gorocksdb_compact_files(db.c, cf, opts.c,
+		&cInputFiles[0], C.size_t(len(cInputFiles)), C.int(outputLevel),
+		&cOutputFiles, &cOutputFilesLens, &cNumOutputFiles, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_compact_files_output_destroy(cOutputFiles, cOutputFilesLens, cNumOutputFiles)
+	return charsToStrings(cOutputFiles, cOutputFilesLens, cNumOutputFiles), nil
+}
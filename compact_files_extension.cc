@@ -0,0 +1,73 @@
+#include "compact_files_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include <string>
+#include <vector>
+#include "rocksdb_internal.h"
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::CompactionOptions;
+using rocksdb::CompressionType;
+
+struct gorocksdb_compactionoptions_t {
+	CompactionOptions rep;
+};
+
+extern "C" {
+
+
+gorocksdb_compactionoptions_t* gorocksdb_compactionoptions_create() {
+	return new gorocksdb_compactionoptions_t;
+}
+
+void gorocksdb_compactionoptions_set_compression(gorocksdb_compactionoptions_t* opts, int v) {
+	opts->rep.compression = static_cast<CompressionType>(v);
+}
+
+void gorocksdb_compactionoptions_set_output_file_size_limit(gorocksdb_compactionoptions_t* opts, uint64_t v) {
+	opts->rep.output_file_size_limit = v;
+}
+
+void gorocksdb_compactionoptions_set_max_subcompactions(gorocksdb_compactionoptions_t* opts, uint32_t v) {
+	opts->rep.max_subcompactions = v;
+}
+
+void gorocksdb_compactionoptions_destroy(gorocksdb_compactionoptions_t* opts) {
+	delete opts;
+}
+
+void gorocksdb_compact_files(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	const gorocksdb_compactionoptions_t* opts,
+	const char* const* input_files, size_t num_input_files, int output_level,
+	char*** output_files, size_t** output_files_lens, size_t* num_output_files, char** errptr) {
+
+	*num_output_files = 0;
+	ColumnFamilyHandle* cf = column_family == NULL ? db->rep->DefaultColumnFamily() : column_family->rep;
+	std::vector<std::string> input(input_files, input_files + num_input_files);
+	std::vector<std::string> output;
+	if (SaveError(errptr, db->rep->CompactFiles(opts->rep, cf, input, output_level, -1, &output))) {
+		return;
+	}
+
+	*num_output_files = output.size();
+	*output_files = static_cast<char**>(malloc(sizeof(char*) * output.size()));
+	*output_files_lens = static_cast<size_t*>(malloc(sizeof(size_t) * output.size()));
+	for (size_t i = 0; i < output.size(); i++) {
+		(*output_files)[i] = static_cast<char*>(malloc(output[i].size()));
+		memcpy((*output_files)[i], output[i].data(), output[i].size());
+		(*output_files_lens)[i] = output[i].size();
+	}
+}
+
+void gorocksdb_compact_files_output_destroy(char** output_files, size_t* output_files_lens, size_t num_output_files) {
+	for (size_t i = 0; i < num_output_files; i++) {
+		free(output_files[i]);
+	}
+	free(output_files);
+	free(output_files_lens);
+}
+
+
+}
@@ -0,0 +1,32 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+/* CompactionOptions */
+
+typedef struct gorocksdb_compactionoptions_t gorocksdb_compactionoptions_t;
+
+gorocksdb_compactionoptions_t* gorocksdb_compactionoptions_create();
+void gorocksdb_compactionoptions_set_compression(gorocksdb_compactionoptions_t* opts, int v);
+void gorocksdb_compactionoptions_set_output_file_size_limit(gorocksdb_compactionoptions_t* opts, uint64_t v);
+void gorocksdb_compactionoptions_set_max_subcompactions(gorocksdb_compactionoptions_t* opts, uint32_t v);
+void gorocksdb_compactionoptions_destroy(gorocksdb_compactionoptions_t* opts);
+
+/* CompactFiles */
+
+// column_family may be NULL for the default column family.
+// The output files have to be freed with gorocksdb_compact_files_output_destroy.
+void gorocksdb_compact_files(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	const gorocksdb_compactionoptions_t* opts,
+	const char* const* input_files, size_t num_input_files, int output_level,
+	char*** output_files, size_t** output_files_lens, size_t* num_output_files, char** errptr);
+
+void gorocksdb_compact_files_output_destroy(char** output_files, size_t* output_files_lens, size_t num_output_files);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -0,0 +1,52 @@
+package gorocksdb
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactFiles(t *testing.T) {
+	db := newTestDB(t, "TestCompactFiles", func(opts *Options) {
+		opts.SetDisableAutoCompactions(true)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, db.Put(wo, []byte("key"+strconv.Itoa(i)), []byte("value")))
+		require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	}
+
+	liveFiles := db.GetLiveFilesMetaData()
+	require.Len(t, liveFiles, 3)
+	// compact the two oldest files.
+	var inputFiles []string
+	for _, liveFile := range liveFiles {
+		if liveFile.SmallestKey[3] != '2' {
+			inputFiles = append(inputFiles, liveFile.Name)
+		}
+	}
+	require.Len(t, inputFiles, 2)
+
+	opts := NewDefaultCompactionOptions()
+	defer opts.Destroy()
+	opts.SetCompression(NoCompression)
+	opts.SetOutputFileSizeLimit(64 << 20)
+
+	outputFiles, err := db.CompactFiles(opts, inputFiles, 1)
+	require.NoError(t, err)
+	require.Len(t, outputFiles, 1)
+
+	metadata := db.GetColumnFamilyMetaData()
+	require.Equal(t, uint64(2), metadata.FileCount)
+	require.Len(t, metadata.Levels[0].Files, 1)
+	require.Len(t, metadata.Levels[1].Files, 1)
+	require.Equal(t, []byte("key0"), metadata.Levels[1].Files[0].SmallestKey)
+	require.Equal(t, []byte("key1"), metadata.Levels[1].Files[0].LargestKey)
+
+	// the input files do not exist anymore.
+	_, err = db.CompactFiles(opts, inputFiles, 1)
+	require.Error(t, err)
+}
@@ -1,7 +1,12 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
+import (
+	"sync"
+	"unsafe"
+)
 
 // A Comparator object provides a total order across slices that are
 // used as keys in an sstable or a database.
@@ -28,22 +33,64 @@ type nativeComparator struct {
 func (c nativeComparator) Compare(a, b []byte) int { return 0 }
 func (c nativeComparator) Name() string            { return "" }
 
+// UnsafeGetNativeComparator returns the underlying c comparator of cmp
+// and a function which releases it.
+// If cmp is not a native comparator, a new c comparator calling into cmp is created,
+// release destroys it and frees the reference to cmp.
+// release must be called once the c comparator is not used anymore.
+func UnsafeGetNativeComparator(cmp Comparator) (ptr unsafe.Pointer, release func()) {
+	if nc, ok := cmp.(nativeComparator); ok {
+		return unsafe.Pointer(nc.c), func() {}
+	}
+	idx := registerComperator(cmp)
+	c := C.gorocksdb_comparator_create(C.uintptr_t(idx))
+	return unsafe.Pointer(c), func() {
+		C.rocksdb_comparator_destroy(c)
+		unregisterComperator(idx)
+	}
+}
+
 // Hold references to comperators.
-var comperators []Comparator
+var (
+	comperatorsMu   sync.RWMutex
+	comperators     []Comparator
+	comperatorsFree []int
+)
 
 func registerComperator(cmp Comparator) int {
+	comperatorsMu.Lock()
+	defer comperatorsMu.Unlock()
+	if n := len(comperatorsFree); n > 0 {
+		idx := comperatorsFree[n-1]
+		comperatorsFree = comperatorsFree[:n-1]
+		comperators[idx] = cmp
+		return idx
+	}
 	comperators = append(comperators, cmp)
 	return len(comperators) - 1
 }
 
+func getComperator(idx int) Comparator {
+	comperatorsMu.RLock()
+	defer comperatorsMu.RUnlock()
+	return comperators[idx]
+}
+
+func unregisterComperator(idx int) {
+	comperatorsMu.Lock()
+	defer comperatorsMu.Unlock()
+	comperators[idx] = nil
+	comperatorsFree = append(comperatorsFree, idx)
+}
+
 //export gorocksdb_comparator_compare
 func gorocksdb_comparator_compare(idx int, cKeyA *C.char, cKeyALen C.size_t, cKeyB *C.char, cKeyBLen C.size_t) C.int {
 	keyA := charToByte(cKeyA, cKeyALen)
 	keyB := charToByte(cKeyB, cKeyBLen)
-	return C.int(comperators[idx].Compare(keyA, keyB))
+	return C.int(getComperator(idx).Compare(keyA, keyB))
 }
 
 //export gorocksdb_comparator_name
 func gorocksdb_comparator_name(idx int) *C.char {
-	return stringToChar(comperators[idx].Name())
+	return stringToChar(getComperator(idx).Name())
 }
@@ -2,9 +2,12 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "db_extension.h"
 import "C"
 import (
+	"context"
 	"errors"
+	"strings"
 	"unsafe"
 )
 
@@ -20,6 +23,8 @@ type DB struct {
 	c    *C.rocksdb_t
 	name string
 	opts *Options
+	// listeners holds the per DB state of the listeners of opts.
+	listeners dbListeners
 }
 
 // OpenDb opens a database with the specified options.
@@ -29,16 +34,38 @@ func OpenDb(opts *Options, name string) (*DB, error) {
 		cName = C.CString(name)
 	)
 	defer C.free(unsafe.Pointer(cName))
-	db := C.rocksdb_open(opts.c, cName, &cErr)
+	db := &DB{name: name, opts: opts}
+	cDBOpts, freeDBOpts := db.listeners.attach(opts)
+	defer freeDBOpts()
+	db.c = C.rocksdb_open(cDBOpts, cName, &cErr)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
+		db.listeners.detach()
 		return nil, errors.New(C.GoString(cErr))
 	}
-	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
-	}, nil
+	return db, nil
+}
+
+// OpenDbWithTTL opens a database with the specified options and TTL support.
+// Keys which are older than ttl seconds are removed during compaction,
+// so they may still be returned after the ttl passed.
+// A ttl <= 0 means infinity.
+func OpenDbWithTTL(opts *Options, name string, ttl int) (*DB, error) {
+	var (
+		cErr  *C.char
+		cName = C.CString(name)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	db := &DB{name: name, opts: opts}
+	cDBOpts, freeDBOpts := db.listeners.attach(opts)
+	defer freeDBOpts()
+	db.c = C.rocksdb_open_with_ttl(cDBOpts, cName, C.int(ttl), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		db.listeners.detach()
+		return nil, errors.New(C.GoString(cErr))
+	}
+	return db, nil
 }
 
 // OpenDbForReadOnly opens a database with the specified options for readonly usage.
@@ -48,16 +75,16 @@ func OpenDbForReadOnly(opts *Options, name string, errorIfLogFileExist bool) (*D
 		cName = C.CString(name)
 	)
 	defer C.free(unsafe.Pointer(cName))
-	db := C.rocksdb_open_for_read_only(opts.c, cName, boolToChar(errorIfLogFileExist), &cErr)
+	db := &DB{name: name, opts: opts}
+	cDBOpts, freeDBOpts := db.listeners.attach(opts)
+	defer freeDBOpts()
+	db.c = C.rocksdb_open_for_read_only(cDBOpts, cName, boolToChar(errorIfLogFileExist), &cErr)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
+		db.listeners.detach()
 		return nil, errors.New(C.GoString(cErr))
 	}
-	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
-	}, nil
+	return db, nil
 }
 
 // OpenDbColumnFamilies opens a database with the specified column families.
@@ -92,9 +119,13 @@ func OpenDbColumnFamilies(
 
 	cHandles := make([]*C.rocksdb_column_family_handle_t, numColumnFamilies)
 
+	db := &DB{name: name, opts: opts}
+	cDBOpts, freeDBOpts := db.listeners.attach(opts)
+	defer freeDBOpts()
+
 	var cErr *C.char
-	db := C.rocksdb_open_column_families(
-		opts.c,
+	db.c = C.rocksdb_open_column_families(
+		cDBOpts,
 		cName,
 		C.int(numColumnFamilies),
 		&cNames[0],
@@ -104,6 +135,7 @@ func OpenDbColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
+		db.listeners.detach()
 		return nil, nil, errors.New(C.GoString(cErr))
 	}
 
@@ -112,11 +144,7 @@ func OpenDbColumnFamilies(
 		cfHandles[i] = NewNativeColumnFamilyHandle(c)
 	}
 
-	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
-	}, cfHandles, nil
+	return db, cfHandles, nil
 }
 
 // OpenDbForReadOnlyColumnFamilies opens a database with the specified column
@@ -153,9 +181,13 @@ func OpenDbForReadOnlyColumnFamilies(
 
 	cHandles := make([]*C.rocksdb_column_family_handle_t, numColumnFamilies)
 
+	db := &DB{name: name, opts: opts}
+	cDBOpts, freeDBOpts := db.listeners.attach(opts)
+	defer freeDBOpts()
+
 	var cErr *C.char
-	db := C.rocksdb_open_for_read_only_column_families(
-		opts.c,
+	db.c = C.rocksdb_open_for_read_only_column_families(
+		cDBOpts,
 		cName,
 		C.int(numColumnFamilies),
 		&cNames[0],
@@ -166,6 +198,7 @@ func OpenDbForReadOnlyColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
+		db.listeners.detach()
 		return nil, nil, errors.New(C.GoString(cErr))
 	}
 
@@ -174,11 +207,7 @@ func OpenDbForReadOnlyColumnFamilies(
 		cfHandles[i] = NewNativeColumnFamilyHandle(c)
 	}
 
-	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
-	}, cfHandles, nil
+	return db, cfHandles, nil
 }
 
 // ListColumnFamilies lists the names of the column families in the DB.
@@ -536,15 +565,24 @@ func (db *DB) GetApproximateSizesCF(cf *ColumnFamilyHandle, ranges []Range) []ui
 
 // LiveFileMetadata is a metadata which is associated with each SST file.
 type LiveFileMetadata struct {
-	Name        string
-	Level       int
-	Size        int64
-	SmallestKey []byte
-	LargestKey  []byte
+	Name             string
+	ColumnFamilyName string
+	Level            int
+	Size             int64
+	SmallestKey      []byte
+	LargestKey       []byte
+	NumEntries       uint64
+	NumDeletions     uint64
+	// UserCollectedProperties are the properties collected by the
+	// TablePropertiesCollectors of the file.
+	// They are only set by GetLiveFilesMetaDataWithProperties.
+	UserCollectedProperties map[string]string
 }
 
 // GetLiveFilesMetaData returns a list of all table files with their
 // level, start key and end key.
+// Use GetLiveFilesMetaDataWithProperties to get the user collected
+// properties of the files as well.
 func (db *DB) GetLiveFilesMetaData() []LiveFileMetadata {
 	lf := C.rocksdb_livefiles(db.c)
 	defer C.rocksdb_livefiles_destroy(lf)
@@ -554,6 +592,7 @@ func (db *DB) GetLiveFilesMetaData() []LiveFileMetadata {
 	for i := C.int(0); i < count; i++ {
 		var liveFile LiveFileMetadata
 		liveFile.Name = C.GoString(C.rocksdb_livefiles_name(lf, i))
+		liveFile.ColumnFamilyName = C.GoString(C.rocksdb_livefiles_column_family_name(lf, i))
 		liveFile.Level = int(C.rocksdb_livefiles_level(lf, i))
 		liveFile.Size = int64(C.rocksdb_livefiles_size(lf, i))
 
@@ -563,6 +602,10 @@ func (db *DB) GetLiveFilesMetaData() []LiveFileMetadata {
 
 		key = C.rocksdb_livefiles_largestkey(lf, i, &cSize)
 		liveFile.LargestKey = C.GoBytes(unsafe.Pointer(key), C.int(cSize))
+
+		liveFile.NumEntries = uint64(C.rocksdb_livefiles_entries(lf, i))
+		liveFile.NumDeletions = uint64(C.rocksdb_livefiles_deletions(lf, i))
+
 		liveFiles[int(i)] = liveFile
 	}
 	return liveFiles
@@ -584,6 +627,91 @@ func (db *DB) CompactRangeCF(cf *ColumnFamilyHandle, r Range) {
 	C.rocksdb_compact_range_cf(db.c, cf.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
 }
 
+// CompactRangeOpt runs a manual compaction with opts on the Range of keys
+// given. A nil Start or Limit means the first or after the last key.
+// Progress is reported through the OnCompactionCompleted events of the
+// EventListeners and the OnCompactionBegin events of the CompactionBeginListeners.
+func (db *DB) CompactRangeOpt(opts *CompactRangeOptions, r Range) error {
+	return db.compactRange(nil, opts, r)
+}
+
+// CompactRangeCFOpt runs a manual compaction with opts on the Range of keys
+// given on the given column family.
+func (db *DB) CompactRangeCFOpt(cf *ColumnFamilyHandle, opts *CompactRangeOptions, r Range) error {
+	return db.compactRange(cf.c, opts, r)
+}
+
+// CompactRangeContext runs a manual compaction like CompactRangeOpt,
+// which is cancelled when ctx is done. Then it returns ctx.Err(), unless
+// the compaction finished or failed before it was cancelled.
+// The compaction is cancelled with DisableManualCompaction, so other
+// manual compactions running at that time are cancelled too.
+func (db *DB) CompactRangeContext(ctx context.Context, opts *CompactRangeOptions, r Range) error {
+	return db.compactRangeContext(ctx, nil, opts, r)
+}
+
+// CompactRangeCFContext runs a manual compaction like CompactRangeCFOpt,
+// which is cancelled when ctx is done. See CompactRangeContext.
+func (db *DB) CompactRangeCFContext(ctx context.Context, cf *ColumnFamilyHandle, opts *CompactRangeOptions, r Range) error {
+	return db.compactRangeContext(ctx, cf.c, opts, r)
+}
+
+func (db *DB) compactRangeContext(ctx context.Context, cf *C.rocksdb_column_family_handle_t, opts *CompactRangeOptions, r Range) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- db.compactRange(cf, opts, r)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		db.DisableManualCompaction()
+		err := <-done
+		db.EnableManualCompaction()
+		if err != nil && isManualCompactionPaused(err) {
+			return ctx.Err()
+		}
+		// the compaction finished or failed before it was cancelled.
+		return err
+	}
+}
+
+// isManualCompactionPaused returns whether err is the status
+// of a manual compaction cancelled by DisableManualCompaction.
+func isManualCompactionPaused(err error) bool {
+	return strings.Contains(err.Error(), "Manual compaction paused")
+}
+
+func (db *DB) compactRange(cf *C.rocksdb_column_family_handle_t, opts *CompactRangeOptions, r Range) error {
+	var cErr *C.char
+	cStart := byteToChar(r.Start)
+	cLimit := byteToChar(r.Limit)
+	C.gorocksdb_compact_range(db.c, cf, opts.c,
+		cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// DisableManualCompaction cancels the running manual compactions and
+// lets new ones fail until EnableManualCompaction is called.
+// Calls are counted, manual compactions are enabled again when
+// EnableManualCompaction was called as often as DisableManualCompaction.
+func (db *DB) DisableManualCompaction() {
+	C.rocksdb_disable_manual_compaction(db.c)
+}
+
+// EnableManualCompaction enables manual compactions
+// after DisableManualCompaction.
+func (db *DB) EnableManualCompaction() {
+	C.rocksdb_enable_manual_compaction(db.c)
+}
+
 // Flush triggers a manuel flush for the database.
 func (db *DB) Flush(opts *FlushOptions) error {
 	var cErr *C.char
@@ -595,7 +723,37 @@ func (db *DB) Flush(opts *FlushOptions) error {
 	return nil
 }
 
+// Resume recovers the database from a background error, for example
+// after space was freed following an out of space error during a flush or compaction.
+// Until then the database is in read-only mode.
+func (db *DB) Resume() error {
+	var cErr *C.char
+	C.gorocksdb_db_resume(db.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	if db.listeners.bgErrState != nil {
+		db.listeners.bgErrState.set(nil)
+	}
+	return nil
+}
+
+// GetBackgroundError returns the current background error of the database
+// or nil if there is none.
+// Requires a handler set with Options.SetBackgroundErrorHandler,
+// otherwise nil is returned always.
+func (db *DB) GetBackgroundError() *BackgroundError {
+	if db.listeners.bgErrState == nil {
+		return nil
+	}
+	return db.listeners.bgErrState.get()
+}
+
 // DisableFileDeletions disables file deletions and should be used when backup the database.
+// Calls are reference counted: file deletions are enabled again after
+// EnableFileDeletions(false) was called as often as DisableFileDeletions,
+// or after EnableFileDeletions(true).
 func (db *DB) DisableFileDeletions() error {
 	var cErr *C.char
 	C.rocksdb_disable_file_deletions(db.c, &cErr)
@@ -606,7 +764,10 @@ func (db *DB) DisableFileDeletions() error {
 	return nil
 }
 
-// EnableFileDeletions enables file deletions for the database.
+// EnableFileDeletions releases one DisableFileDeletions call or,
+// if force is true, all of them and enables file deletions for the database.
+// Whether file deletions are enabled is returned by the property
+// PropertyIsFileDeletionsEnabled.
 func (db *DB) EnableFileDeletions(force bool) error {
 	var cErr *C.char
 	C.rocksdb_enable_file_deletions(db.c, boolToChar(force), &cErr)
@@ -617,6 +778,38 @@ func (db *DB) EnableFileDeletions(force bool) error {
 	return nil
 }
 
+// PauseBackgroundWork waits for the running flushes and compactions
+// to finish and does not schedule new ones until ContinueBackgroundWork
+// is called. Calls are counted like DisableFileDeletions.
+// Writes stall if the memtables are full while background work is paused.
+func (db *DB) PauseBackgroundWork() error {
+	var cErr *C.char
+	C.gorocksdb_pause_background_work(db.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// ContinueBackgroundWork resumes the background work after PauseBackgroundWork.
+func (db *DB) ContinueBackgroundWork() error {
+	var cErr *C.char
+	C.gorocksdb_continue_background_work(db.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// CancelAllBackgroundWork cancels the scheduled flushes and compactions
+// and prepares the DB for Close, it should not be used afterwards.
+// If wait is true, it returns after the running jobs have finished.
+func (db *DB) CancelAllBackgroundWork(wait bool) {
+	C.rocksdb_cancel_all_background_work(db.c, boolToChar(wait))
+}
+
 // DeleteFile deletes the file name from the db directory and update the internal state to
 // reflect that. Supports deletion of sst and log files only. 'name' must be
 // path relative to the db directory. eg. 000001.sst, /archive/000003.log.
@@ -685,6 +878,71 @@ func (db *DB) IngestExternalFileCF(handle *ColumnFamilyHandle, filePaths []strin
 	return nil
 }
 
+// IngestExternalFileArg describes the files IngestExternalFiles
+// ingests into one column family.
+type IngestExternalFileArg struct {
+	// ColumnFamily nil means the default column family.
+	ColumnFamily *ColumnFamilyHandle
+	FilePaths    []string
+	// Options nil means the default IngestExternalFileOptions.
+	Options *IngestExternalFileOptions
+}
+
+// IngestExternalFiles loads lists of external SST files into multiple
+// column families atomically: either all files are ingested or none.
+// Each column family may appear only once in args.
+func (db *DB) IngestExternalFiles(args []IngestExternalFileArg) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	cCFs := make([]*C.rocksdb_column_family_handle_t, len(args))
+	cOpts := make([]*C.rocksdb_ingestexternalfileoptions_t, len(args))
+	cNumFiles := make([]C.size_t, len(args))
+	var cFilePaths []*C.char
+	defer func() {
+		for _, s := range cFilePaths {
+			C.free(unsafe.Pointer(s))
+		}
+	}()
+	for i, arg := range args {
+		if arg.ColumnFamily != nil {
+			cCFs[i] = arg.ColumnFamily.c
+		}
+		if arg.Options != nil {
+			cOpts[i] = arg.Options.c
+		}
+		cNumFiles[i] = C.size_t(len(arg.FilePaths))
+		for _, s := range arg.FilePaths {
+			cFilePaths = append(cFilePaths, C.CString(s))
+		}
+	}
+	if len(cFilePaths) == 0 {
+		return errors.New("no files to ingest")
+	}
+
+	var cErr *C.char
+	C.gorocksdb_ingest_external_files(
+		db.c,
+		C.size_t(len(args)),
+		&cCFs[0],
+		&cFilePaths[0],
+		&cNumFiles[0],
+		&cOpts[0],
+		&cErr,
+	)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// baseDB implements BackupableDB.
+func (db *DB) baseDB() *C.rocksdb_t {
+	return db.c
+}
+
 // NewCheckpoint creates a new Checkpoint for this db.
 func (db *DB) NewCheckpoint() (*Checkpoint, error) {
 	var (
@@ -702,8 +960,18 @@ func (db *DB) NewCheckpoint() (*Checkpoint, error) {
 }
 
 // Close closes the database.
+// Call CancelAllBackgroundWork first to drain the running
+// flushes and compactions.
 func (db *DB) Close() {
 	C.rocksdb_close(db.c)
+	db.listeners.detach()
+}
+
+// DroppedEventCount returns the number of events which were not passed
+// to the EventListeners of the database because a listener fell behind,
+// see EventListener.
+func (db *DB) DroppedEventCount() uint64 {
+	return db.listeners.droppedEvents()
 }
 
 // DestroyDb removes a database entirely, removing everything from the
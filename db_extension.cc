@@ -0,0 +1,174 @@
+#include "db_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include <map>
+#include <string>
+#include <vector>
+#include "rocksdb_internal.h"
+#include "rocksdb/transaction_log.h"
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::IngestExternalFileArg;
+using rocksdb::VectorLogPtr;
+using rocksdb::Slice;
+using rocksdb::Status;
+
+extern "C" {
+
+
+void gorocksdb_db_resume(rocksdb_t* db, char** errptr) {
+	SaveError(errptr, db->rep->Resume());
+}
+
+void gorocksdb_pause_background_work(rocksdb_t* db, char** errptr) {
+	SaveError(errptr, db->rep->PauseBackgroundWork());
+}
+
+void gorocksdb_continue_background_work(rocksdb_t* db, char** errptr) {
+	SaveError(errptr, db->rep->ContinueBackgroundWork());
+}
+
+rocksdb_t* gorocksdb_transactiondb_get_base_db(rocksdb_transactiondb_t* txn_db) {
+	rocksdb_t* base_db = new rocksdb_t;
+	base_db->rep = txn_db->rep;
+	return base_db;
+}
+
+void gorocksdb_transactiondb_base_db_destroy(rocksdb_t* base_db) {
+	delete base_db;
+}
+
+static ColumnFamilyHandle* cf_handle(rocksdb_t* db, rocksdb_column_family_handle_t* column_family) {
+	if (column_family == NULL) {
+		return db->rep->DefaultColumnFamily();
+	}
+	return column_family->rep;
+}
+
+static char* copy_string(const std::string& s) {
+	char* c = static_cast<char*>(malloc(s.size()));
+	memcpy(c, s.data(), s.size());
+	return c;
+}
+
+unsigned char gorocksdb_property_aggregated_int(
+	rocksdb_t* db, const char* name, size_t name_len, uint64_t* value) {
+
+	return db->rep->GetAggregatedIntProperty(Slice(name, name_len), value);
+}
+
+unsigned char gorocksdb_property_map(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	const char* name, size_t name_len,
+	char*** keys, size_t** keys_lens, char*** values, size_t** values_lens, size_t* num) {
+
+	std::map<std::string, std::string> m;
+	if (!db->rep->GetMapProperty(cf_handle(db, column_family), Slice(name, name_len), &m)) {
+		return 0;
+	}
+
+	*num = m.size();
+	*keys = static_cast<char**>(malloc(sizeof(char*) * m.size()));
+	*keys_lens = static_cast<size_t*>(malloc(sizeof(size_t) * m.size()));
+	*values = static_cast<char**>(malloc(sizeof(char*) * m.size()));
+	*values_lens = static_cast<size_t*>(malloc(sizeof(size_t) * m.size()));
+	size_t i = 0;
+	for (const auto& kv : m) {
+		(*keys)[i] = copy_string(kv.first);
+		(*keys_lens)[i] = kv.first.size();
+		(*values)[i] = copy_string(kv.second);
+		(*values_lens)[i] = kv.second.size();
+		i++;
+	}
+	return 1;
+}
+
+void gorocksdb_property_map_destroy(
+	char** keys, size_t* keys_lens, char** values, size_t* values_lens, size_t num) {
+
+	for (size_t i = 0; i < num; i++) {
+		free(keys[i]);
+		free(values[i]);
+	}
+	free(keys);
+	free(keys_lens);
+	free(values);
+	free(values_lens);
+}
+
+void gorocksdb_ingest_external_files(
+	rocksdb_t* db, size_t num_args,
+	rocksdb_column_family_handle_t** column_families,
+	const char* const* file_paths, const size_t* num_files,
+	rocksdb_ingestexternalfileoptions_t** opts, char** errptr) {
+
+	std::vector<IngestExternalFileArg> args(num_args);
+	for (size_t i = 0; i < num_args; i++) {
+		args[i].column_family = cf_handle(db, column_families[i]);
+		args[i].external_files.assign(file_paths, file_paths + num_files[i]);
+		if (opts[i] != NULL) {
+			args[i].options = opts[i]->rep;
+		}
+		file_paths += num_files[i];
+	}
+	SaveError(errptr, db->rep->IngestExternalFiles(args));
+}
+
+void gorocksdb_compact_range(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	const rocksdb_compactoptions_t* opts,
+	const char* start, size_t start_len, const char* limit, size_t limit_len, char** errptr) {
+
+	Slice start_slice, limit_slice;
+	if (start != NULL) {
+		start_slice = Slice(start, start_len);
+	}
+	if (limit != NULL) {
+		limit_slice = Slice(limit, limit_len);
+	}
+	SaveError(errptr, db->rep->CompactRange(
+		opts->rep, cf_handle(db, column_family),
+		start != NULL ? &start_slice : NULL,
+		limit != NULL ? &limit_slice : NULL));
+}
+
+gorocksdb_wal_file_t* gorocksdb_get_sorted_wal_files(rocksdb_t* db, size_t* num, char** errptr) {
+	*num = 0;
+	VectorLogPtr files;
+	if (SaveError(errptr, db->rep->GetSortedWalFiles(files))) {
+		return NULL;
+	}
+
+	std::string wal_dir = db->rep->GetDBOptions().wal_dir;
+	if (wal_dir.empty()) {
+		wal_dir = db->rep->GetName();
+	}
+
+	gorocksdb_wal_file_t* c_files = static_cast<gorocksdb_wal_file_t*>(
+		calloc(files.size(), sizeof(gorocksdb_wal_file_t)));
+	for (size_t i = 0; i < files.size(); i++) {
+		const std::string path_name = files[i]->PathName();
+		gorocksdb_wal_file_t* c_file = &c_files[i];
+		c_file->path_name = copy_string(path_name);
+		c_file->path_name_len = path_name.size();
+		c_file->log_number = files[i]->LogNumber();
+		c_file->alive = files[i]->Type() == rocksdb::kAliveLogFile;
+		c_file->start_sequence = files[i]->StartSequence();
+		c_file->size_file_bytes = files[i]->SizeFileBytes();
+		// the file may have been archived or deleted in the meantime.
+		db->rep->GetEnv()->GetFileModificationTime(wal_dir + path_name, &c_file->modification_time);
+	}
+	*num = files.size();
+	return c_files;
+}
+
+void gorocksdb_wal_files_destroy(gorocksdb_wal_file_t* files, size_t num) {
+	for (size_t i = 0; i < num; i++) {
+		free(files[i].path_name);
+	}
+	free(files);
+}
+
+
+}
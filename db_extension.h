@@ -0,0 +1,78 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+void gorocksdb_db_resume(rocksdb_t* db, char** errptr);
+
+/* Background work */
+
+void gorocksdb_pause_background_work(rocksdb_t* db, char** errptr);
+void gorocksdb_continue_background_work(rocksdb_t* db, char** errptr);
+
+/* Ingestion */
+
+// Ingests the files into the column families atomically. The files of
+// column_families[i] are the next num_files[i] entries of file_paths.
+// A NULL column family means the default column family,
+// NULL opts the default IngestExternalFileOptions.
+void gorocksdb_ingest_external_files(
+	rocksdb_t* db, size_t num_args,
+	rocksdb_column_family_handle_t** column_families,
+	const char* const* file_paths, const size_t* num_files,
+	rocksdb_ingestexternalfileoptions_t** opts, char** errptr);
+
+/* Compaction */
+
+// column_family may be NULL for the default column family.
+// start and limit may be NULL for the first and after the last key.
+void gorocksdb_compact_range(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	const rocksdb_compactoptions_t* opts,
+	const char* start, size_t start_len, const char* limit, size_t limit_len, char** errptr);
+
+/* WAL files */
+
+typedef struct {
+	char* path_name;
+	size_t path_name_len;
+	uint64_t log_number;
+	unsigned char alive;
+	uint64_t start_sequence;
+	uint64_t size_file_bytes;
+	uint64_t modification_time;
+} gorocksdb_wal_file_t;
+
+// The files have to be freed with gorocksdb_wal_files_destroy.
+gorocksdb_wal_file_t* gorocksdb_get_sorted_wal_files(rocksdb_t* db, size_t* num, char** errptr);
+
+void gorocksdb_wal_files_destroy(gorocksdb_wal_file_t* files, size_t num);
+
+/* TransactionDB */
+
+// Returns a handle to the transaction db as plain db, for example to back it up.
+// The handle must not be closed but freed with gorocksdb_transactiondb_base_db_destroy.
+rocksdb_t* gorocksdb_transactiondb_get_base_db(rocksdb_transactiondb_t* txn_db);
+
+void gorocksdb_transactiondb_base_db_destroy(rocksdb_t* base_db);
+
+/* Properties */
+
+unsigned char gorocksdb_property_aggregated_int(
+	rocksdb_t* db, const char* name, size_t name_len, uint64_t* value);
+
+// column_family may be NULL for the default column family.
+// The keys and values have to be freed with gorocksdb_property_map_destroy.
+unsigned char gorocksdb_property_map(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+	const char* name, size_t name_len,
+	char*** keys, size_t** keys_lens, char*** values, size_t** values_lens, size_t* num);
+
+void gorocksdb_property_map_destroy(
+	char** keys, size_t* keys_lens, char** values, size_t* values_lens, size_t num);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -33,7 +33,7 @@ func TestExternalFile(t *testing.T) {
 	err = w.Add([]byte("ddd"), []byte("dddValue"))
 	require.NoError(t, err)
 
-	err = w.Finish()
+	_, err = w.Finish()
 	require.NoError(t, err)
 
 	ingestOpts := NewDefaultIngestExternalFileOptions()
@@ -87,7 +87,7 @@ func TestExternalFileCFs(t *testing.T) {
 	err = w.Add([]byte("ddd"), []byte("dddValue"))
 	require.NoError(t, err)
 
-	err = w.Finish()
+	_, err = w.Finish()
 	require.NoError(t, err)
 
 	ingestOpts := NewDefaultIngestExternalFileOptions()
@@ -115,3 +115,176 @@ func TestExternalFileCFs(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, v4, []byte("dddValue"))
 }
+
+func TestExternalFileDeletions(t *testing.T) {
+	db := newTestDB(t, "TestExternalFileDeletions", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, key := range []string{"aaa", "bbb", "ccc", "ddd"} {
+		require.NoError(t, db.Put(wo, []byte(key), []byte("old")))
+	}
+
+	envOpts := NewDefaultEnvOptions()
+	opts := NewDefaultOptions()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-test")
+	require.NoError(t, err)
+	defer os.Remove(filePath.Name())
+
+	require.NoError(t, w.Open(filePath.Name()))
+	require.NoError(t, w.Put([]byte("aaa"), []byte("aaaValue")))
+	require.NoError(t, w.Delete([]byte("bbb")))
+	require.NoError(t, w.DeleteRange([]byte("ccc"), []byte("ddd")))
+	require.NoError(t, w.Put([]byte("eee"), []byte("eeeValue")))
+
+	info, err := w.Finish()
+	require.NoError(t, err)
+	require.Equal(t, filePath.Name(), info.FilePath)
+	require.Equal(t, []byte("aaa"), info.SmallestKey)
+	require.Equal(t, []byte("eee"), info.LargestKey)
+	require.Equal(t, []byte("ccc"), info.SmallestRangeDelKey)
+	require.Equal(t, []byte("ddd"), info.LargestRangeDelKey)
+	require.Equal(t, uint64(3), info.NumEntries)
+	require.Equal(t, uint64(1), info.NumRangeDelEntries)
+	require.True(t, info.FileSize > 0)
+	require.Equal(t, info.FileSize, w.FileSize())
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	require.NoError(t, db.IngestExternalFile([]string{filePath.Name()}, ingestOpts))
+
+	readOpts := NewDefaultReadOptions()
+	for key, value := range map[string][]byte{
+		"aaa": []byte("aaaValue"),
+		"bbb": nil,
+		"ccc": nil,
+		"ddd": []byte("old"),
+		"eee": []byte("eeeValue"),
+	} {
+		v, err := db.GetBytes(readOpts, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, v)
+	}
+}
+
+func TestExternalFileWriterCF(t *testing.T) {
+	db, cfs := newTestDBCFs(t, "TestExternalFileWriterCF", []string{"default", "cf1"}, nil)
+	defer db.Close()
+
+	envOpts := NewDefaultEnvOptions()
+	opts := NewDefaultOptions()
+	w := NewSSTFileWriterCF(envOpts, opts, cfs[1])
+	defer w.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-test")
+	require.NoError(t, err)
+	defer os.Remove(filePath.Name())
+
+	require.NoError(t, w.Open(filePath.Name()))
+	require.NoError(t, w.Put([]byte("aaa"), []byte("aaaValue")))
+	_, err = w.Finish()
+	require.NoError(t, err)
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	require.Error(t, db.IngestExternalFileCF(cfs[0], []string{filePath.Name()}, ingestOpts))
+	require.NoError(t, db.IngestExternalFileCF(cfs[1], []string{filePath.Name()}, ingestOpts))
+
+	v, err := db.GetCF(NewDefaultReadOptions(), cfs[1], []byte("aaa"))
+	defer CfreeByteSlice(v)
+	require.NoError(t, err)
+	require.Equal(t, []byte("aaaValue"), v)
+}
+
+func newTestSstFile(t *testing.T, opts *Options, kvs ...string) string {
+	w := NewSSTFileWriter(NewDefaultEnvOptions(), opts)
+	defer w.Destroy()
+
+	f, err := ioutil.TempFile("", "sst-file-test")
+	require.NoError(t, err)
+	require.NoError(t, w.Open(f.Name()))
+	for i := 0; i < len(kvs); i += 2 {
+		require.NoError(t, w.Put([]byte(kvs[i]), []byte(kvs[i+1])))
+	}
+	_, err = w.Finish()
+	require.NoError(t, err)
+	return f.Name()
+}
+
+func TestIngestExternalFiles(t *testing.T) {
+	db, cfs := newTestDBCFs(t, "TestIngestExternalFiles", []string{"default", "cf1"}, nil)
+	defer db.Close()
+
+	opts := NewDefaultOptions()
+	file1 := newTestSstFile(t, opts, "aaa", "aaaValue")
+	defer os.Remove(file1)
+	file2 := newTestSstFile(t, opts, "bbb", "bbbValue")
+	defer os.Remove(file2)
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	ingestOpts.SetVerifyChecksumsBeforeIngest(true)
+	ingestOpts.SetWriteGlobalSeqno(false)
+	ingestOpts.SetFailIfNotBottommostLevel(false)
+
+	require.NoError(t, db.IngestExternalFiles([]IngestExternalFileArg{
+		{ColumnFamily: cfs[0], FilePaths: []string{file1}, Options: ingestOpts},
+		{ColumnFamily: cfs[1], FilePaths: []string{file2}, Options: ingestOpts},
+	}))
+
+	readOpts := NewDefaultReadOptions()
+	v1, err := db.GetCF(readOpts, cfs[0], []byte("aaa"))
+	defer CfreeByteSlice(v1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("aaaValue"), v1)
+	v2, err := db.GetCF(readOpts, cfs[1], []byte("bbb"))
+	defer CfreeByteSlice(v2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("bbbValue"), v2)
+
+	// a missing file fails the ingestion into both column families.
+	file3 := newTestSstFile(t, opts, "ccc", "cccValue")
+	defer os.Remove(file3)
+	require.Error(t, db.IngestExternalFiles([]IngestExternalFileArg{
+		{ColumnFamily: cfs[0], FilePaths: []string{file3}, Options: ingestOpts},
+		{ColumnFamily: cfs[1], FilePaths: []string{file3 + ".missing"}, Options: ingestOpts},
+	}))
+	v3, err := db.GetCF(readOpts, cfs[0], []byte("ccc"))
+	require.NoError(t, err)
+	require.Nil(t, v3)
+
+	// nil means the default column family and options.
+	require.NoError(t, db.IngestExternalFiles([]IngestExternalFileArg{
+		{FilePaths: []string{file3}},
+	}))
+	v4, err := db.GetCF(readOpts, cfs[0], []byte("ccc"))
+	defer CfreeByteSlice(v4)
+	require.NoError(t, err)
+	require.Equal(t, []byte("cccValue"), v4)
+}
+
+func TestIngestBehind(t *testing.T) {
+	db := newTestDB(t, "TestIngestBehind", func(opts *Options) {
+		opts.SetAllowIngestBehind(true)
+	})
+	defer db.Close()
+	require.NoError(t, db.Put(NewDefaultWriteOptions(), []byte("aaa"), []byte("new")))
+
+	file := newTestSstFile(t, NewDefaultOptions(), "aaa", "old", "bbb", "bbbValue")
+	defer os.Remove(file)
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	ingestOpts.SetIngestBehind(true)
+	require.NoError(t, db.IngestExternalFile([]string{file}, ingestOpts))
+
+	readOpts := NewDefaultReadOptions()
+	for key, value := range map[string][]byte{"aaa": []byte("new"), "bbb": []byte("bbbValue")} {
+		v, err := db.GetBytes(readOpts, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, v)
+	}
+}
@@ -0,0 +1,145 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "db_extension.h"
+import "C"
+import (
+	"strconv"
+	"unsafe"
+)
+
+// Names of the string properties, see DB::Properties in rocksdb/db.h.
+const (
+	PropertyNumFilesAtLevelPrefix         = "rocksdb.num-files-at-level"
+	PropertyCompressionRatioAtLevelPrefix = "rocksdb.compression-ratio-at-level"
+	PropertyStats                         = "rocksdb.stats"
+	PropertySSTables                      = "rocksdb.sstables"
+	PropertyCFStatsNoFileHistogram        = "rocksdb.cfstats-no-file-histogram"
+	PropertyCFFileHistogram               = "rocksdb.cf-file-histogram"
+	PropertyLevelStats                    = "rocksdb.levelstats"
+)
+
+// Names of the map properties, which can be read with GetMapProperty.
+const (
+	PropertyCFStats                   = "rocksdb.cfstats"
+	PropertyDBStats                   = "rocksdb.dbstats"
+	PropertyAggregatedTableProperties = "rocksdb.aggregated-table-properties"
+)
+
+// Names of the integer properties, which can be read with GetIntProperty.
+const (
+	PropertyNumImmutableMemTable           = "rocksdb.num-immutable-mem-table"
+	PropertyNumImmutableMemTableFlushed    = "rocksdb.num-immutable-mem-table-flushed"
+	PropertyMemTableFlushPending           = "rocksdb.mem-table-flush-pending"
+	PropertyNumRunningFlushes              = "rocksdb.num-running-flushes"
+	PropertyCompactionPending              = "rocksdb.compaction-pending"
+	PropertyNumRunningCompactions          = "rocksdb.num-running-compactions"
+	PropertyBackgroundErrors               = "rocksdb.background-errors"
+	PropertyCurSizeActiveMemTable          = "rocksdb.cur-size-active-mem-table"
+	PropertyCurSizeAllMemTables            = "rocksdb.cur-size-all-mem-tables"
+	PropertySizeAllMemTables               = "rocksdb.size-all-mem-tables"
+	PropertyNumEntriesActiveMemTable       = "rocksdb.num-entries-active-mem-table"
+	PropertyNumEntriesImmMemTables         = "rocksdb.num-entries-imm-mem-tables"
+	PropertyNumDeletesActiveMemTable       = "rocksdb.num-deletes-active-mem-table"
+	PropertyNumDeletesImmMemTables         = "rocksdb.num-deletes-imm-mem-tables"
+	PropertyEstimateNumKeys                = "rocksdb.estimate-num-keys"
+	PropertyEstimateTableReadersMem        = "rocksdb.estimate-table-readers-mem"
+	PropertyIsFileDeletionsEnabled         = "rocksdb.is-file-deletions-enabled"
+	PropertyNumSnapshots                   = "rocksdb.num-snapshots"
+	PropertyOldestSnapshotTime             = "rocksdb.oldest-snapshot-time"
+	PropertyNumLiveVersions                = "rocksdb.num-live-versions"
+	PropertyCurrentSuperVersionNumber      = "rocksdb.current-super-version-number"
+	PropertyEstimateLiveDataSize           = "rocksdb.estimate-live-data-size"
+	PropertyMinLogNumberToKeep             = "rocksdb.min-log-number-to-keep"
+	PropertyMinObsoleteSstNumberToKeep     = "rocksdb.min-obsolete-sst-number-to-keep"
+	PropertyTotalSstFilesSize              = "rocksdb.total-sst-files-size"
+	PropertyLiveSstFilesSize               = "rocksdb.live-sst-files-size"
+	PropertyBaseLevel                      = "rocksdb.base-level"
+	PropertyEstimatePendingCompactionBytes = "rocksdb.estimate-pending-compaction-bytes"
+	PropertyActualDelayedWriteRate         = "rocksdb.actual-delayed-write-rate"
+	PropertyIsWriteStopped                 = "rocksdb.is-write-stopped"
+	PropertyEstimateOldestKeyTime          = "rocksdb.estimate-oldest-key-time"
+	PropertyBlockCacheCapacity             = "rocksdb.block-cache-capacity"
+	PropertyBlockCacheUsage                = "rocksdb.block-cache-usage"
+	PropertyBlockCachePinnedUsage          = "rocksdb.block-cache-pinned-usage"
+)
+
+// PropertyNumFilesAtLevel returns the name of the property
+// with the number of files at level.
+func PropertyNumFilesAtLevel(level int) string {
+	return PropertyNumFilesAtLevelPrefix + strconv.Itoa(level)
+}
+
+// GetIntProperty returns the value of an integer property of the default column family.
+// It returns false if propName is not an integer property.
+func (db *DB) GetIntProperty(propName string) (uint64, bool) {
+	return db.getIntProperty(propName, nil)
+}
+
+// GetIntPropertyCF returns the value of an integer property of a column family.
+// It returns false if propName is not an integer property.
+func (db *DB) GetIntPropertyCF(propName string, cf *ColumnFamilyHandle) (uint64, bool) {
+	return db.getIntProperty(propName, cf.c)
+}
+
+func (db *DB) getIntProperty(propName string, cf *C.rocksdb_column_family_handle_t) (uint64, bool) {
+	var (
+		cValue C.uint64_t
+		ret    C.int
+	)
+	cProp := C.CString(propName)
+	defer C.free(unsafe.Pointer(cProp))
+	if cf == nil {
+		ret = C.rocksdb_property_int(db.c, cProp, &cValue)
+	} else {
+		ret = C.rocksdb_property_int_cf(db.c, cf, cProp, &cValue)
+	}
+	// the C API returns 0 on success.
+	return uint64(cValue), ret == 0
+}
+
+// GetAggregatedIntProperty returns the sum of an integer property
+// over all column families.
+// It returns false if propName is not an integer property.
+func (db *DB) GetAggregatedIntProperty(propName string) (uint64, bool) {
+	var cValue C.uint64_t
+	name := []byte(propName)
+	ok := C.gorocksdb_property_aggregated_int(db.c, byteToChar(name), C.size_t(len(name)), &cValue)
+	return uint64(cValue), ok != 0
+}
+
+// GetMapProperty returns the value of a map property of the default column family,
+// for example PropertyCFStats.
+// It returns false if propName is not a map property.
+func (db *DB) GetMapProperty(propName string) (map[string]string, bool) {
+	return db.getMapProperty(propName, nil)
+}
+
+// GetMapPropertyCF returns the value of a map property of a column family.
+// It returns false if propName is not a map property.
+func (db *DB) GetMapPropertyCF(propName string, cf *ColumnFamilyHandle) (map[string]string, bool) {
+	return db.getMapProperty(propName, cf.c)
+}
+
+func (db *DB) getMapProperty(propName string, cf *C.rocksdb_column_family_handle_t) (map[string]string, bool) {
+	var (
+		cKeys, cValues         **C.char
+		cKeysLens, cValuesLens *C.size_t
+		cNum                   C.size_t
+	)
+	name := []byte(propName)
+	if C.gorocksdb_property_map(db.c, cf, byteToChar(name), C.size_t(len(name)),
+		&cKeys, &cKeysLens, &cValues, &cValuesLens, &cNum) == 0 {
+		return nil, false
+	}
+	defer C.gorocksdb_property_map_destroy(cKeys, cKeysLens, cValues, cValuesLens, cNum)
+
+	keys := charsToStrings(cKeys, cKeysLens, cNum)
+	values := charsToStrings(cValues, cValuesLens, cNum)
+	m := make(map[string]string, len(keys))
+	for i, key := range keys {
+		m[key] = values[i]
+	}
+	return m, true
+}
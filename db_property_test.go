@@ -0,0 +1,48 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDBIntProperty(t *testing.T) {
+	db, cfs := newTestDBCFs(t, "TestDBIntProperty", []string{"default", "other"}, nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key1"), []byte("value")))
+	require.NoError(t, db.PutCF(wo, cfs[1], []byte("key1"), []byte("value")))
+	require.NoError(t, db.PutCF(wo, cfs[1], []byte("key2"), []byte("value")))
+
+	v, ok := db.GetIntProperty(PropertyNumEntriesActiveMemTable)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), v)
+
+	v, ok = db.GetIntPropertyCF(PropertyNumEntriesActiveMemTable, cfs[1])
+	require.True(t, ok)
+	require.Equal(t, uint64(2), v)
+
+	v, ok = db.GetAggregatedIntProperty(PropertyNumEntriesActiveMemTable)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), v)
+
+	_, ok = db.GetIntProperty(PropertyStats)
+	require.False(t, ok)
+}
+
+func TestDBMapProperty(t *testing.T) {
+	db, cfs := newTestDBCFs(t, "TestDBMapProperty", []string{"default", "other"}, nil)
+	defer db.Close()
+
+	m, ok := db.GetMapProperty(PropertyCFStats)
+	require.True(t, ok)
+	require.NotEmpty(t, m)
+
+	m, ok = db.GetMapPropertyCF(PropertyCFStats, cfs[1])
+	require.True(t, ok)
+	require.NotEmpty(t, m)
+
+	_, ok = db.GetMapProperty(PropertyEstimateNumKeys)
+	require.False(t, ok)
+	require.Equal(t, "rocksdb.num-files-at-level2", PropertyNumFilesAtLevel(2))
+}
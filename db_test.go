@@ -1,10 +1,12 @@
 package gorocksdb
 
 import (
+	"context"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestOpenDb(t *testing.T) {
@@ -241,6 +243,190 @@ func TestDBCRUDDBPaths(t *testing.T) {
 	require.True(t, v3 == nil)
 }
 
+type compactionBeginRecorder struct {
+	EmptyEventListener
+	begins chan *CompactionJobInfo
+}
+
+func (l *compactionBeginRecorder) OnCompactionBegin(info *CompactionJobInfo) {
+	l.begins <- info
+}
+
+func TestDBCompactRangeOpt(t *testing.T) {
+	listener := &compactionBeginRecorder{begins: make(chan *CompactionJobInfo, 16)}
+	db := newTestDB(t, "TestDBCompactRangeOpt", func(opts *Options) {
+		opts.AddEventListener(listener)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.Put(wo, []byte("key"+strconv.Itoa(i)), []byte("value")))
+		require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	}
+
+	opts := NewDefaultCompactRangeOptions()
+	defer opts.Destroy()
+	opts.SetChangeLevel(true)
+	opts.SetTargetLevel(3)
+	opts.SetBottommostLevelCompaction(BottommostLevelCompactionForce)
+	opts.SetAllowWriteStall(true)
+
+	// disabled manual compactions fail.
+	db.DisableManualCompaction()
+	require.Error(t, db.CompactRangeOpt(opts, Range{nil, nil}))
+	db.EnableManualCompaction()
+
+	require.NoError(t, db.CompactRangeOpt(opts, Range{nil, nil}))
+	select {
+	case info := <-listener.begins:
+		require.Len(t, info.InputFiles, 2)
+		require.Equal(t, CompactionReasonManualCompaction, info.Reason)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no compaction begin event")
+	}
+
+	liveFiles := db.GetLiveFilesMetaData()
+	require.Len(t, liveFiles, 1)
+	require.Equal(t, 3, liveFiles[0].Level)
+}
+
+func TestDBCompactRangeContext(t *testing.T) {
+	db := newTestDB(t, "TestDBCompactRangeContext", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.Put(wo, []byte("key"+strconv.Itoa(i)), []byte("value")))
+		require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	}
+
+	opts := NewDefaultCompactRangeOptions()
+	defer opts.Destroy()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, db.CompactRangeContext(ctx, opts, Range{nil, nil}))
+	require.Len(t, db.GetLiveFilesMetaData(), 2)
+
+	require.NoError(t, db.CompactRangeContext(context.Background(), opts, Range{nil, nil}))
+	require.Len(t, db.GetLiveFilesMetaData(), 1)
+}
+
+// slowCompactionFilter keeps every key but slows down the compaction.
+type slowCompactionFilter struct{}
+
+func (slowCompactionFilter) Filter(level int, key, val []byte) (remove bool, newVal []byte) {
+	time.Sleep(time.Millisecond)
+	return false, nil
+}
+
+func (slowCompactionFilter) Name() string { return "gorocksdb.slow" }
+
+func TestDBCompactRangeContextCancelRunning(t *testing.T) {
+	listener := &compactionBeginRecorder{begins: make(chan *CompactionJobInfo, 16)}
+	db := newTestDB(t, "TestDBCompactRangeContextCancelRunning", func(opts *Options) {
+		opts.SetDisableAutoCompactions(true)
+		opts.SetCompactionFilter(slowCompactionFilter{})
+		opts.AddEventListener(listener)
+	})
+	defer db.Close()
+
+	// the compaction takes at least 20 seconds.
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 10000; j++ {
+			key := []byte("key" + strconv.Itoa(i) + "-" + strconv.Itoa(j))
+			require.NoError(t, db.Put(wo, key, []byte("value")))
+		}
+		require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	}
+
+	opts := NewDefaultCompactRangeOptions()
+	defer opts.Destroy()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- db.CompactRangeContext(ctx, opts, Range{nil, nil})
+	}()
+
+	select {
+	case info := <-listener.begins:
+		require.Equal(t, CompactionReasonManualCompaction, info.Reason)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no compaction begin event")
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		require.Equal(t, context.Canceled, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the compaction was not cancelled")
+	}
+	require.Len(t, db.GetLiveFilesMetaData(), 2)
+}
+
+func TestDBFileDeletions(t *testing.T) {
+	db := newTestDB(t, "TestDBFileDeletions", nil)
+	defer db.Close()
+
+	isEnabled := func() bool {
+		enabled, ok := db.GetIntProperty(PropertyIsFileDeletionsEnabled)
+		require.True(t, ok)
+		return enabled != 0
+	}
+
+	require.NoError(t, db.DisableFileDeletions())
+	require.NoError(t, db.DisableFileDeletions())
+	require.False(t, isEnabled())
+
+	require.NoError(t, db.EnableFileDeletions(false))
+	require.False(t, isEnabled())
+
+	require.NoError(t, db.EnableFileDeletions(false))
+	require.True(t, isEnabled())
+
+	require.NoError(t, db.DisableFileDeletions())
+	require.NoError(t, db.DisableFileDeletions())
+	require.NoError(t, db.EnableFileDeletions(true))
+	require.True(t, isEnabled())
+}
+
+func TestDBBackgroundWork(t *testing.T) {
+	db := newTestDB(t, "TestDBBackgroundWork", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	fo.SetWait(false)
+
+	require.NoError(t, db.PauseBackgroundWork())
+	require.NoError(t, db.Put(wo, []byte("key"), []byte("value")))
+	require.NoError(t, db.Flush(fo))
+
+	// the flush does not run while background work is paused.
+	time.Sleep(100 * time.Millisecond)
+	require.Empty(t, db.GetLiveFilesMetaData())
+	numImm, ok := db.GetIntProperty(PropertyNumImmutableMemTable)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), numImm)
+
+	require.NoError(t, db.ContinueBackgroundWork())
+	deadline := time.Now().Add(10 * time.Second)
+	for len(db.GetLiveFilesMetaData()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the flush did not finish after ContinueBackgroundWork")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	db.CancelAllBackgroundWork(true)
+}
+
 func newTestDB(t *testing.T, name string, applyOpts func(opts *Options)) *DB {
 	dir, err := ioutil.TempDir("", "gorocksdb-"+name)
 	require.NoError(t, err)
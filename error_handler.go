@@ -0,0 +1,210 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "event_listener_extension.h"
+import "C"
+import "sync"
+
+// BackgroundErrorReason describes the operation which caused a background error.
+type BackgroundErrorReason int
+
+// Background error reasons.
+const (
+	BackgroundErrorReasonFlush              = BackgroundErrorReason(0)
+	BackgroundErrorReasonCompaction         = BackgroundErrorReason(1)
+	BackgroundErrorReasonWriteCallback      = BackgroundErrorReason(2)
+	BackgroundErrorReasonMemTable           = BackgroundErrorReason(3)
+	BackgroundErrorReasonManifestWrite      = BackgroundErrorReason(4)
+	BackgroundErrorReasonFlushNoWAL         = BackgroundErrorReason(5)
+	BackgroundErrorReasonManifestWriteNoWAL = BackgroundErrorReason(6)
+)
+
+// String returns the name of the reason.
+func (r BackgroundErrorReason) String() string {
+	switch r {
+	case BackgroundErrorReasonFlush:
+		return "flush"
+	case BackgroundErrorReasonCompaction:
+		return "compaction"
+	case BackgroundErrorReasonWriteCallback:
+		return "write callback"
+	case BackgroundErrorReasonMemTable:
+		return "memtable"
+	case BackgroundErrorReasonManifestWrite:
+		return "manifest write"
+	case BackgroundErrorReasonFlushNoWAL:
+		return "flush no wal"
+	case BackgroundErrorReasonManifestWriteNoWAL:
+		return "manifest write no wal"
+	}
+	return "unknown"
+}
+
+// ErrorSeverity describes how severe a background error is.
+// With a soft error the DB stays writable, with a hard error the DB becomes
+// read-only until it recovered or Resume is called.
+// Fatal and unrecoverable errors require reopening the DB.
+type ErrorSeverity int
+
+// Error severities.
+const (
+	ErrorSeverityNoError       = ErrorSeverity(0)
+	ErrorSeveritySoft          = ErrorSeverity(1)
+	ErrorSeverityHard          = ErrorSeverity(2)
+	ErrorSeverityFatal         = ErrorSeverity(3)
+	ErrorSeverityUnrecoverable = ErrorSeverity(4)
+)
+
+// String returns the name of the severity.
+func (s ErrorSeverity) String() string {
+	switch s {
+	case ErrorSeverityNoError:
+		return "no error"
+	case ErrorSeveritySoft:
+		return "soft"
+	case ErrorSeverityHard:
+		return "hard"
+	case ErrorSeverityFatal:
+		return "fatal"
+	case ErrorSeverityUnrecoverable:
+		return "unrecoverable"
+	}
+	return "unknown"
+}
+
+// BackgroundError is an error of a background operation like a flush or compaction.
+type BackgroundError struct {
+	Reason   BackgroundErrorReason
+	Severity ErrorSeverity
+	Message  string
+}
+
+// Error implements the error interface.
+func (e *BackgroundError) Error() string {
+	return e.Reason.String() + " (" + e.Severity.String() + "): " + e.Message
+}
+
+// A BackgroundErrorHandler is notified about errors of background operations.
+//
+// The methods are called from the background threads of rocksdb
+// and must not block.
+type BackgroundErrorHandler interface {
+	// OnBackgroundError is called if a background operation failed.
+	// Returning true suppresses the error, so the DB does not switch
+	// to read-only mode. Only do that if the error is known to be harmless.
+	OnBackgroundError(bgErr *BackgroundError) (suppress bool)
+
+	// OnErrorRecoveryCompleted is called if the DB automatically recovered
+	// from the background error oldErr, for example
+	// after space was freed by the SstFileManager.
+	OnErrorRecoveryCompleted(oldErr *BackgroundError)
+}
+
+// NoopBackgroundErrorHandler does nothing and can be used
+// if only DB.GetBackgroundError is needed.
+type NoopBackgroundErrorHandler struct{}
+
+// OnBackgroundError implements BackgroundErrorHandler.
+func (NoopBackgroundErrorHandler) OnBackgroundError(bgErr *BackgroundError) bool { return false }
+
+// OnErrorRecoveryCompleted implements BackgroundErrorHandler.
+func (NoopBackgroundErrorHandler) OnErrorRecoveryCompleted(oldErr *BackgroundError) {}
+
+// backgroundErrorState tracks the current background error of a DB.
+type backgroundErrorState struct {
+	handler BackgroundErrorHandler
+	idx     int
+
+	mu  sync.Mutex
+	err *BackgroundError
+}
+
+func newBackgroundErrorState(handler BackgroundErrorHandler) *backgroundErrorState {
+	state := &backgroundErrorState{handler: handler}
+	state.idx = registerBackgroundErrorState(state)
+	return state
+}
+
+func (s *backgroundErrorState) set(err *BackgroundError) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func (s *backgroundErrorState) get() *BackgroundError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Hold references to background error states.
+var (
+	backgroundErrorStatesMu   sync.RWMutex
+	backgroundErrorStates     []*backgroundErrorState
+	backgroundErrorStatesFree []int
+)
+
+func registerBackgroundErrorState(state *backgroundErrorState) int {
+	backgroundErrorStatesMu.Lock()
+	defer backgroundErrorStatesMu.Unlock()
+	if n := len(backgroundErrorStatesFree); n > 0 {
+		idx := backgroundErrorStatesFree[n-1]
+		backgroundErrorStatesFree = backgroundErrorStatesFree[:n-1]
+		backgroundErrorStates[idx] = state
+		return idx
+	}
+	backgroundErrorStates = append(backgroundErrorStates, state)
+	return len(backgroundErrorStates) - 1
+}
+
+func unregisterBackgroundErrorState(idx int) {
+	backgroundErrorStatesMu.Lock()
+	defer backgroundErrorStatesMu.Unlock()
+	backgroundErrorStates[idx] = nil
+	backgroundErrorStatesFree = append(backgroundErrorStatesFree, idx)
+}
+
+func getBackgroundErrorState(idx C.uintptr_t) *backgroundErrorState {
+	backgroundErrorStatesMu.RLock()
+	defer backgroundErrorStatesMu.RUnlock()
+	return backgroundErrorStates[idx]
+}
+
+// SetBackgroundErrorHandler sets the handler which is notified about
+// background errors of the DBs opened with these options.
+// It also enables DB.GetBackgroundError, the background error
+// is tracked for each DB separately.
+// Must be called before opening the DB.
+// Default: nil
+func (opts *Options) SetBackgroundErrorHandler(handler BackgroundErrorHandler) {
+	opts.bgErrHandler = handler
+}
+
+//export gorocksdb_backgrounderror_on_error
+func gorocksdb_backgrounderror_on_error(idx C.uintptr_t, reason C.int, severity C.int, cMsg *C.char, cMsgLen C.size_t) C.uchar {
+	state := getBackgroundErrorState(idx)
+	bgErr := &BackgroundError{
+		Reason:   BackgroundErrorReason(reason),
+		Severity: ErrorSeverity(severity),
+		Message:  charToString(cMsg, cMsgLen),
+	}
+	if state.handler.OnBackgroundError(bgErr) {
+		return boolToChar(true)
+	}
+	state.set(bgErr)
+	return boolToChar(false)
+}
+
+//export gorocksdb_backgrounderror_on_recovery_completed
+func gorocksdb_backgrounderror_on_recovery_completed(idx C.uintptr_t, severity C.int, cMsg *C.char, cMsgLen C.size_t) {
+	state := getBackgroundErrorState(idx)
+	oldErr := state.get()
+	if oldErr == nil {
+		oldErr = &BackgroundError{
+			Severity: ErrorSeverity(severity),
+			Message:  charToString(cMsg, cMsgLen),
+		}
+	}
+	state.set(nil)
+	state.handler.OnErrorRecoveryCompleted(oldErr)
+}
@@ -0,0 +1,114 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+// testBackgroundErrorHandler is called from the threads of rocksdb.
+type testBackgroundErrorHandler struct {
+	mu        sync.Mutex
+	errs      []*BackgroundError
+	recovered []*BackgroundError
+}
+
+func (h *testBackgroundErrorHandler) OnBackgroundError(bgErr *BackgroundError) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errs = append(h.errs, bgErr)
+	return false
+}
+
+func (h *testBackgroundErrorHandler) OnErrorRecoveryCompleted(oldErr *BackgroundError) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.recovered = append(h.recovered, oldErr)
+}
+
+func (h *testBackgroundErrorHandler) getErrs() []*BackgroundError {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*BackgroundError(nil), h.errs...)
+}
+
+// failingMergeOperator fails every full merge,
+// which fails the flush of a put followed by a merge.
+type failingMergeOperator struct{}
+
+func (failingMergeOperator) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	return nil, false
+}
+
+func (failingMergeOperator) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return nil, false
+}
+
+func (failingMergeOperator) Name() string { return "gorocksdb.failing" }
+
+func TestBackgroundErrorHandler(t *testing.T) {
+	handler := &testBackgroundErrorHandler{}
+	db := newTestDB(t, "TestBackgroundErrorHandler", func(opts *Options) {
+		opts.SetBackgroundErrorHandler(handler)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key"), []byte("value")))
+	require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	require.Nil(t, db.GetBackgroundError())
+	require.Empty(t, handler.getErrs())
+
+	bgErr := &BackgroundError{
+		Reason:   BackgroundErrorReasonFlush,
+		Severity: ErrorSeverityHard,
+		Message:  "IO error: No space left on device",
+	}
+	require.Equal(t, "flush (hard): IO error: No space left on device", bgErr.Error())
+
+	// simulate a tracked background error, Resume must clear it.
+	db.listeners.bgErrState.set(bgErr)
+	require.Equal(t, bgErr, db.GetBackgroundError())
+	require.NoError(t, db.Resume())
+	require.Nil(t, db.GetBackgroundError())
+}
+
+func TestBackgroundErrorHandlerFailedFlush(t *testing.T) {
+	handler := &testBackgroundErrorHandler{}
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	opts.SetMergeOperator(failingMergeOperator{})
+	opts.SetBackgroundErrorHandler(handler)
+
+	// the background error is tracked for each DB opened with opts.
+	dir, err := ioutil.TempDir("", "gorocksdb-TestBackgroundErrorHandlerFailedFlush")
+	require.NoError(t, err)
+	db, err := OpenDb(opts, dir)
+	require.NoError(t, err)
+	defer db.Close()
+	otherDir, err := ioutil.TempDir("", "gorocksdb-TestBackgroundErrorHandlerFailedFlushOther")
+	require.NoError(t, err)
+	other, err := OpenDb(opts, otherDir)
+	require.NoError(t, err)
+	defer other.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key"), []byte("value")))
+	require.NoError(t, db.Merge(wo, []byte("key"), []byte("operand")))
+	require.Error(t, db.Flush(NewDefaultFlushOptions()))
+
+	errs := handler.getErrs()
+	require.Len(t, errs, 1)
+	require.Equal(t, BackgroundErrorReasonFlush, errs[0].Reason)
+	require.Equal(t, errs[0], db.GetBackgroundError())
+	require.Nil(t, other.GetBackgroundError())
+}
+
+func TestBackgroundErrorNoHandler(t *testing.T) {
+	db := newTestDB(t, "TestBackgroundErrorNoHandler", nil)
+	defer db.Close()
+
+	require.Nil(t, db.GetBackgroundError())
+	require.NoError(t, db.Resume())
+}
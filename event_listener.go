@@ -0,0 +1,473 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "event_listener_extension.h"
+import "C"
+import (
+	"errors"
+	"sync"
+)
+
+// FlushJobInfo describes a completed flush.
+type FlushJobInfo struct {
+	ColumnFamilyName string
+	// FilePath is the path of the newly created file.
+	FilePath string
+	ThreadID uint64
+	JobID    int
+	// TriggeredWritesSlowdown is true if writes were slowed down
+	// because of too many files in level 0.
+	TriggeredWritesSlowdown bool
+	// TriggeredWritesStop is true if writes were stopped
+	// because of too many files in level 0.
+	TriggeredWritesStop bool
+	SmallestSeqno       uint64
+	LargestSeqno        uint64
+	TableProperties     TableProperties
+}
+
+// CompactionReason describes why a compaction was started.
+type CompactionReason int
+
+// Compaction reasons.
+const (
+	CompactionReasonUnknown                    = CompactionReason(0)
+	CompactionReasonLevelL0FilesNum            = CompactionReason(1)
+	CompactionReasonLevelMaxLevelSize          = CompactionReason(2)
+	CompactionReasonUniversalSizeAmplification = CompactionReason(3)
+	CompactionReasonUniversalSizeRatio         = CompactionReason(4)
+	CompactionReasonUniversalSortedRunNum      = CompactionReason(5)
+	CompactionReasonFIFOMaxSize                = CompactionReason(6)
+	CompactionReasonFIFOReduceNumFiles         = CompactionReason(7)
+	CompactionReasonFIFOTtl                    = CompactionReason(8)
+	CompactionReasonManualCompaction           = CompactionReason(9)
+	CompactionReasonFilesMarkedForCompaction   = CompactionReason(10)
+)
+
+// CompactionJobInfo describes a compaction. The output files and
+// the statistics are only set when the compaction has finished.
+type CompactionJobInfo struct {
+	ColumnFamilyName string
+	// Err is set if the compaction failed.
+	Err            error
+	ThreadID       uint64
+	JobID          int
+	BaseInputLevel int
+	OutputLevel    int
+	InputFiles     []string
+	OutputFiles    []string
+	Reason         CompactionReason
+
+	ElapsedMicros    uint64
+	NumInputRecords  uint64
+	NumOutputRecords uint64
+	TotalInputBytes  uint64
+	TotalOutputBytes uint64
+}
+
+// TableFileCreationReason describes why a table file was created.
+type TableFileCreationReason int
+
+// Table file creation reasons.
+const (
+	TableFileCreationReasonFlush      = TableFileCreationReason(0)
+	TableFileCreationReasonCompaction = TableFileCreationReason(1)
+	TableFileCreationReasonRecovery   = TableFileCreationReason(2)
+	TableFileCreationReasonMisc       = TableFileCreationReason(3)
+)
+
+// TableFileCreationInfo describes a created table file.
+type TableFileCreationInfo struct {
+	DBName           string
+	ColumnFamilyName string
+	FilePath         string
+	// Err is set if the file creation failed.
+	Err             error
+	JobID           int
+	Reason          TableFileCreationReason
+	FileSize        uint64
+	TableProperties TableProperties
+}
+
+// TableFileDeletionInfo describes a deleted table file.
+type TableFileDeletionInfo struct {
+	DBName   string
+	FilePath string
+	// Err is set if the file deletion failed.
+	Err   error
+	JobID int
+}
+
+// WriteStallCondition describes whether writes are stalled.
+type WriteStallCondition int
+
+// Write stall conditions.
+const (
+	WriteStallConditionNormal  = WriteStallCondition(0)
+	WriteStallConditionDelayed = WriteStallCondition(1)
+	WriteStallConditionStopped = WriteStallCondition(2)
+)
+
+// WriteStallInfo describes a change of the write stall condition of a column family.
+type WriteStallInfo struct {
+	ColumnFamilyName string
+	Cur              WriteStallCondition
+	Prev             WriteStallCondition
+}
+
+// ExternalFileIngestionInfo describes an ingested external file.
+type ExternalFileIngestionInfo struct {
+	ColumnFamilyName string
+	// ExternalFilePath is the path of the file outside the DB.
+	ExternalFilePath string
+	// InternalFilePath is the path of the file inside the DB.
+	InternalFilePath string
+	// GlobalSeqno is the global sequence number assigned to the keys of the file.
+	GlobalSeqno     uint64
+	TableProperties TableProperties
+}
+
+// An EventListener is notified about events of the DB.
+//
+// The events of each DB are queued and passed to the listener on a separate
+// goroutine in the order they happened, so slow handlers do not block
+// the background threads of rocksdb. The queue holds up to 65536 events,
+// further events are dropped and counted in DB.DroppedEventCount until
+// the handler caught up. Close delivers the queued events before it returns.
+// Embed EmptyEventListener to implement only some of the methods.
+type EventListener interface {
+	// OnFlushCompleted is called when a flush has finished.
+	OnFlushCompleted(info *FlushJobInfo)
+
+	// OnCompactionCompleted is called when a compaction has finished.
+	OnCompactionCompleted(info *CompactionJobInfo)
+
+	// OnTableFileCreated is called when a table file was created.
+	OnTableFileCreated(info *TableFileCreationInfo)
+
+	// OnTableFileDeleted is called when a table file was deleted.
+	OnTableFileDeleted(info *TableFileDeletionInfo)
+
+	// OnStallConditionsChanged is called when writes start or stop to be
+	// delayed or stopped.
+	OnStallConditionsChanged(info *WriteStallInfo)
+
+	// OnExternalFileIngested is called when an external file was ingested.
+	OnExternalFileIngested(info *ExternalFileIngestionInfo)
+}
+
+// A CompactionBeginListener is an EventListener which is also notified
+// when a compaction starts. The listeners passed to AddEventListener are
+// checked for this interface.
+type CompactionBeginListener interface {
+	EventListener
+
+	// OnCompactionBegin is called when a compaction starts.
+	OnCompactionBegin(info *CompactionJobInfo)
+}
+
+// EmptyEventListener implements EventListener and ignores all events.
+type EmptyEventListener struct{}
+
+// OnFlushCompleted implements EventListener.
+func (EmptyEventListener) OnFlushCompleted(info *FlushJobInfo) {}
+
+// OnCompactionCompleted implements EventListener.
+func (EmptyEventListener) OnCompactionCompleted(info *CompactionJobInfo) {}
+
+// OnTableFileCreated implements EventListener.
+func (EmptyEventListener) OnTableFileCreated(info *TableFileCreationInfo) {}
+
+// OnTableFileDeleted implements EventListener.
+func (EmptyEventListener) OnTableFileDeleted(info *TableFileDeletionInfo) {}
+
+// OnStallConditionsChanged implements EventListener.
+func (EmptyEventListener) OnStallConditionsChanged(info *WriteStallInfo) {}
+
+// OnExternalFileIngested implements EventListener.
+func (EmptyEventListener) OnExternalFileIngested(info *ExternalFileIngestionInfo) {}
+
+// maxEventListenerQueueLen is the number of events a queue holds at most.
+const maxEventListenerQueueLen = 1 << 16
+
+// eventListenerQueue delivers the events to the listener on its own goroutine.
+type eventListenerQueue struct {
+	listener EventListener
+	idx      int
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	events  []func(EventListener)
+	dropped uint64
+	closed  bool
+	// done is closed once run returned.
+	done chan struct{}
+}
+
+func newEventListenerQueue(listener EventListener) *eventListenerQueue {
+	q := &eventListenerQueue{listener: listener, done: make(chan struct{})}
+	q.cond = sync.NewCond(&q.mu)
+	q.idx = registerEventListenerQueue(q)
+	go q.run()
+	return q
+}
+
+func (q *eventListenerQueue) push(event func(EventListener)) {
+	q.mu.Lock()
+	if len(q.events) >= maxEventListenerQueueLen {
+		q.dropped++
+		q.mu.Unlock()
+		return
+	}
+	q.events = append(q.events, event)
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+func (q *eventListenerQueue) run() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for len(q.events) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		events := q.events
+		q.events = nil
+		closed := q.closed
+		q.mu.Unlock()
+
+		if len(events) == 0 && closed {
+			return
+		}
+		for _, event := range events {
+			event(q.listener)
+		}
+	}
+}
+
+// close delivers the queued events, stops the goroutine and
+// releases the registry index. No events must be pushed anymore.
+func (q *eventListenerQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.cond.Signal()
+	<-q.done
+	unregisterEventListenerQueue(q.idx)
+}
+
+func (q *eventListenerQueue) droppedEvents() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// Hold references to event listener queues.
+var (
+	eventListenerQueuesMu   sync.RWMutex
+	eventListenerQueues     []*eventListenerQueue
+	eventListenerQueuesFree []int
+)
+
+func registerEventListenerQueue(q *eventListenerQueue) int {
+	eventListenerQueuesMu.Lock()
+	defer eventListenerQueuesMu.Unlock()
+	if n := len(eventListenerQueuesFree); n > 0 {
+		idx := eventListenerQueuesFree[n-1]
+		eventListenerQueuesFree = eventListenerQueuesFree[:n-1]
+		eventListenerQueues[idx] = q
+		return idx
+	}
+	eventListenerQueues = append(eventListenerQueues, q)
+	return len(eventListenerQueues) - 1
+}
+
+func unregisterEventListenerQueue(idx int) {
+	eventListenerQueuesMu.Lock()
+	defer eventListenerQueuesMu.Unlock()
+	eventListenerQueues[idx] = nil
+	eventListenerQueuesFree = append(eventListenerQueuesFree, idx)
+}
+
+func getEventListenerQueue(idx C.uintptr_t) *eventListenerQueue {
+	eventListenerQueuesMu.RLock()
+	defer eventListenerQueuesMu.RUnlock()
+	return eventListenerQueues[idx]
+}
+
+// AddEventListener adds a listener which is notified about flushes, compactions,
+// table file creations and deletions, write stalls and ingested external files
+// of the DBs opened with these options.
+// Each DB opened with these options gets its own queue of events.
+// Must be called before opening the DB.
+func (opts *Options) AddEventListener(listener EventListener) {
+	opts.eventListeners = append(opts.eventListeners, listener)
+}
+
+// dbListeners holds the per DB state of the listeners of the Options
+// a DB was opened with.
+type dbListeners struct {
+	queues []*eventListenerQueue
+	// bgErrState is set if the Options have a BackgroundErrorHandler.
+	bgErrState *backgroundErrorState
+}
+
+// attach returns the options to open a DB with, a copy of opts.c with the
+// listeners of opts attached to l, and a function which frees the copy
+// after opening. Without listeners opts.c is returned.
+func (l *dbListeners) attach(opts *Options) (*C.rocksdb_options_t, func()) {
+	if len(opts.eventListeners) == 0 && opts.bgErrHandler == nil {
+		return opts.c, func() {}
+	}
+	c := C.rocksdb_options_create_copy(opts.c)
+	for _, listener := range opts.eventListeners {
+		q := newEventListenerQueue(listener)
+		l.queues = append(l.queues, q)
+		C.gorocksdb_options_add_eventlistener(c, C.uintptr_t(q.idx))
+	}
+	if opts.bgErrHandler != nil {
+		l.bgErrState = newBackgroundErrorState(opts.bgErrHandler)
+		C.gorocksdb_options_add_backgrounderror_listener(c, C.uintptr_t(l.bgErrState.idx))
+	}
+	return c, func() { C.rocksdb_options_destroy(c) }
+}
+
+// detach delivers the queued events and releases the listeners.
+// It must be called after the DB was closed or failed to open.
+func (l *dbListeners) detach() {
+	for _, q := range l.queues {
+		q.close()
+	}
+	l.queues = nil
+	if l.bgErrState != nil {
+		unregisterBackgroundErrorState(l.bgErrState.idx)
+		l.bgErrState = nil
+	}
+}
+
+func (l *dbListeners) droppedEvents() (dropped uint64) {
+	for _, q := range l.queues {
+		dropped += q.droppedEvents()
+	}
+	return
+}
+
+// statusToError returns nil for an empty status message.
+func statusToError(data *C.char, len C.size_t) error {
+	if len == 0 {
+		return nil
+	}
+	return errors.New(charToString(data, len))
+}
+
+//export gorocksdb_eventlistener_on_flush_completed
+func gorocksdb_eventlistener_on_flush_completed(idx C.uintptr_t, cInfo *C.gorocksdb_flushjobinfo_t) {
+	info := &FlushJobInfo{
+		ColumnFamilyName:        charToString(cInfo.cf_name, cInfo.cf_name_len),
+		FilePath:                charToString(cInfo.file_path, cInfo.file_path_len),
+		ThreadID:                uint64(cInfo.thread_id),
+		JobID:                   int(cInfo.job_id),
+		TriggeredWritesSlowdown: cInfo.triggered_writes_slowdown != 0,
+		TriggeredWritesStop:     cInfo.triggered_writes_stop != 0,
+		SmallestSeqno:           uint64(cInfo.smallest_seqno),
+		LargestSeqno:            uint64(cInfo.largest_seqno),
+		TableProperties:         tablePropertiesFromC(&cInfo.table_properties),
+	}
+	getEventListenerQueue(idx).push(func(listener EventListener) {
+		listener.OnFlushCompleted(info)
+	})
+}
+
+//export gorocksdb_eventlistener_on_compaction_begin
+func gorocksdb_eventlistener_on_compaction_begin(idx C.uintptr_t, cInfo *C.gorocksdb_compactionjobinfo_t) {
+	if _, ok := getEventListenerQueue(idx).listener.(CompactionBeginListener); !ok {
+		return
+	}
+	info := compactionJobInfoFromC(cInfo)
+	getEventListenerQueue(idx).push(func(listener EventListener) {
+		listener.(CompactionBeginListener).OnCompactionBegin(info)
+	})
+}
+
+//export gorocksdb_eventlistener_on_compaction_completed
+func gorocksdb_eventlistener_on_compaction_completed(idx C.uintptr_t, cInfo *C.gorocksdb_compactionjobinfo_t) {
+	info := compactionJobInfoFromC(cInfo)
+	getEventListenerQueue(idx).push(func(listener EventListener) {
+		listener.OnCompactionCompleted(info)
+	})
+}
+
+func compactionJobInfoFromC(cInfo *C.gorocksdb_compactionjobinfo_t) *CompactionJobInfo {
+	return &CompactionJobInfo{
+		ColumnFamilyName: charToString(cInfo.cf_name, cInfo.cf_name_len),
+		Err:              statusToError(cInfo.status, cInfo.status_len),
+		ThreadID:         uint64(cInfo.thread_id),
+		JobID:            int(cInfo.job_id),
+		BaseInputLevel:   int(cInfo.base_input_level),
+		OutputLevel:      int(cInfo.output_level),
+		InputFiles:       charsToStrings(cInfo.input_files, cInfo.input_files_lens, cInfo.num_input_files),
+		OutputFiles:      charsToStrings(cInfo.output_files, cInfo.output_files_lens, cInfo.num_output_files),
+		Reason:           CompactionReason(cInfo.compaction_reason),
+		ElapsedMicros:    uint64(cInfo.elapsed_micros),
+		NumInputRecords:  uint64(cInfo.num_input_records),
+		NumOutputRecords: uint64(cInfo.num_output_records),
+		TotalInputBytes:  uint64(cInfo.total_input_bytes),
+		TotalOutputBytes: uint64(cInfo.total_output_bytes),
+	}
+}
+
+//export gorocksdb_eventlistener_on_table_file_created
+func gorocksdb_eventlistener_on_table_file_created(idx C.uintptr_t, cInfo *C.gorocksdb_tablefilecreationinfo_t) {
+	info := &TableFileCreationInfo{
+		DBName:           charToString(cInfo.db_name, cInfo.db_name_len),
+		ColumnFamilyName: charToString(cInfo.cf_name, cInfo.cf_name_len),
+		FilePath:         charToString(cInfo.file_path, cInfo.file_path_len),
+		Err:              statusToError(cInfo.status, cInfo.status_len),
+		JobID:            int(cInfo.job_id),
+		Reason:           TableFileCreationReason(cInfo.reason),
+		FileSize:         uint64(cInfo.file_size),
+		TableProperties:  tablePropertiesFromC(&cInfo.table_properties),
+	}
+	getEventListenerQueue(idx).push(func(listener EventListener) {
+		listener.OnTableFileCreated(info)
+	})
+}
+
+//export gorocksdb_eventlistener_on_table_file_deleted
+func gorocksdb_eventlistener_on_table_file_deleted(idx C.uintptr_t, cInfo *C.gorocksdb_tablefiledeletioninfo_t) {
+	info := &TableFileDeletionInfo{
+		DBName:   charToString(cInfo.db_name, cInfo.db_name_len),
+		FilePath: charToString(cInfo.file_path, cInfo.file_path_len),
+		Err:      statusToError(cInfo.status, cInfo.status_len),
+		JobID:    int(cInfo.job_id),
+	}
+	getEventListenerQueue(idx).push(func(listener EventListener) {
+		listener.OnTableFileDeleted(info)
+	})
+}
+
+//export gorocksdb_eventlistener_on_stall_conditions_changed
+func gorocksdb_eventlistener_on_stall_conditions_changed(idx C.uintptr_t, cInfo *C.gorocksdb_writestallinfo_t) {
+	info := &WriteStallInfo{
+		ColumnFamilyName: charToString(cInfo.cf_name, cInfo.cf_name_len),
+		Cur:              WriteStallCondition(cInfo.cur),
+		Prev:             WriteStallCondition(cInfo.prev),
+	}
+	getEventListenerQueue(idx).push(func(listener EventListener) {
+		listener.OnStallConditionsChanged(info)
+	})
+}
+
+//export gorocksdb_eventlistener_on_external_file_ingested
+func gorocksdb_eventlistener_on_external_file_ingested(idx C.uintptr_t, cInfo *C.gorocksdb_externalfileingestioninfo_t) {
+	info := &ExternalFileIngestionInfo{
+		ColumnFamilyName: charToString(cInfo.cf_name, cInfo.cf_name_len),
+		ExternalFilePath: charToString(cInfo.external_file_path, cInfo.external_file_path_len),
+		InternalFilePath: charToString(cInfo.internal_file_path, cInfo.internal_file_path_len),
+		GlobalSeqno:      uint64(cInfo.global_seqno),
+		TableProperties:  tablePropertiesFromC(&cInfo.table_properties),
+	}
+	getEventListenerQueue(idx).push(func(listener EventListener) {
+		listener.OnExternalFileIngested(info)
+	})
+}
@@ -0,0 +1,218 @@
+#include "event_listener_extension.h"
+
+#include <stdlib.h>
+#include <string>
+#include <memory>
+#include <vector>
+#include "rocksdb/listener.h"
+#include "rocksdb_internal.h"
+
+using rocksdb::BackgroundErrorReason;
+using rocksdb::CompactionJobInfo;
+using rocksdb::DB;
+using rocksdb::EventListener;
+using rocksdb::ExternalFileIngestionInfo;
+using rocksdb::FlushJobInfo;
+using rocksdb::Status;
+using rocksdb::TableFileCreationInfo;
+using rocksdb::TableFileDeletionInfo;
+using rocksdb::WriteStallCondition;
+using rocksdb::WriteStallInfo;
+
+static char* str_data(const std::string& s) {
+	return const_cast<char*>(s.data());
+}
+
+// The values of the WriteStallCondition enum differ between rocksdb versions.
+static int write_stall_condition(WriteStallCondition cond) {
+	switch (cond) {
+	case WriteStallCondition::kDelayed:
+		return 1;
+	case WriteStallCondition::kStopped:
+		return 2;
+	default:
+		return 0;
+	}
+}
+
+static void set_strings(
+	const std::vector<std::string>& src,
+	std::vector<char*>* ptrs, std::vector<size_t>* lens) {
+
+	ptrs->reserve(src.size());
+	lens->reserve(src.size());
+	for (const std::string& s : src) {
+		ptrs->push_back(str_data(s));
+		lens->push_back(s.size());
+	}
+}
+
+// on_compaction passes a copy of cji to the Go callback.
+static void on_compaction(
+	uintptr_t idx, const CompactionJobInfo& cji,
+	void (*callback)(uintptr_t idx, gorocksdb_compactionjobinfo_t* info)) {
+
+	std::string status = cji.status.ToString();
+	std::vector<char*> input_files, output_files;
+	std::vector<size_t> input_files_lens, output_files_lens;
+	set_strings(cji.input_files, &input_files, &input_files_lens);
+	set_strings(cji.output_files, &output_files, &output_files_lens);
+
+	gorocksdb_compactionjobinfo_t info;
+	info.cf_name = str_data(cji.cf_name);
+	info.cf_name_len = cji.cf_name.size();
+	info.status = str_data(status);
+	info.status_len = cji.status.ok() ? 0 : status.size();
+	info.thread_id = cji.thread_id;
+	info.job_id = cji.job_id;
+	info.base_input_level = cji.base_input_level;
+	info.output_level = cji.output_level;
+	info.input_files = input_files.data();
+	info.input_files_lens = input_files_lens.data();
+	info.num_input_files = input_files.size();
+	info.output_files = output_files.data();
+	info.output_files_lens = output_files_lens.data();
+	info.num_output_files = output_files.size();
+	info.compaction_reason = static_cast<int>(cji.compaction_reason);
+	info.elapsed_micros = cji.stats.elapsed_micros;
+	info.num_input_records = cji.stats.num_input_records;
+	info.num_output_records = cji.stats.num_output_records;
+	info.total_input_bytes = cji.stats.total_input_bytes;
+	info.total_output_bytes = cji.stats.total_output_bytes;
+	callback(idx, &info);
+}
+
+// GoEventListener copies the event infos and passes them to the
+// EventListener registered in Go at idx.
+// The Go side queues the events, so the background threads do not
+// wait for the handlers.
+class GoEventListener : public EventListener {
+public:
+	explicit GoEventListener(uintptr_t idx) : idx_(idx) {}
+
+	void OnFlushCompleted(DB* db, const FlushJobInfo& fji) override {
+		gorocksdb_flushjobinfo_t info;
+		info.cf_name = str_data(fji.cf_name);
+		info.cf_name_len = fji.cf_name.size();
+		info.file_path = str_data(fji.file_path);
+		info.file_path_len = fji.file_path.size();
+		info.thread_id = fji.thread_id;
+		info.job_id = fji.job_id;
+		info.triggered_writes_slowdown = fji.triggered_writes_slowdown;
+		info.triggered_writes_stop = fji.triggered_writes_stop;
+		info.smallest_seqno = fji.smallest_seqno;
+		info.largest_seqno = fji.largest_seqno;
+		gorocksdb_set_tableproperties(&info.table_properties, fji.table_properties);
+		gorocksdb_eventlistener_on_flush_completed(idx_, &info);
+	}
+
+	void OnCompactionBegin(DB* db, const CompactionJobInfo& cji) override {
+		on_compaction(idx_, cji, gorocksdb_eventlistener_on_compaction_begin);
+	}
+
+	void OnCompactionCompleted(DB* db, const CompactionJobInfo& cji) override {
+		on_compaction(idx_, cji, gorocksdb_eventlistener_on_compaction_completed);
+	}
+
+	void OnTableFileCreated(const TableFileCreationInfo& tfci) override {
+		std::string status = tfci.status.ToString();
+
+		gorocksdb_tablefilecreationinfo_t info;
+		info.db_name = str_data(tfci.db_name);
+		info.db_name_len = tfci.db_name.size();
+		info.cf_name = str_data(tfci.cf_name);
+		info.cf_name_len = tfci.cf_name.size();
+		info.file_path = str_data(tfci.file_path);
+		info.file_path_len = tfci.file_path.size();
+		info.status = str_data(status);
+		info.status_len = tfci.status.ok() ? 0 : status.size();
+		info.job_id = tfci.job_id;
+		info.reason = static_cast<int>(tfci.reason);
+		info.file_size = tfci.file_size;
+		gorocksdb_set_tableproperties(&info.table_properties, tfci.table_properties);
+		gorocksdb_eventlistener_on_table_file_created(idx_, &info);
+	}
+
+	void OnTableFileDeleted(const TableFileDeletionInfo& tfdi) override {
+		std::string status = tfdi.status.ToString();
+
+		gorocksdb_tablefiledeletioninfo_t info;
+		info.db_name = str_data(tfdi.db_name);
+		info.db_name_len = tfdi.db_name.size();
+		info.file_path = str_data(tfdi.file_path);
+		info.file_path_len = tfdi.file_path.size();
+		info.status = str_data(status);
+		info.status_len = tfdi.status.ok() ? 0 : status.size();
+		info.job_id = tfdi.job_id;
+		gorocksdb_eventlistener_on_table_file_deleted(idx_, &info);
+	}
+
+	void OnStallConditionsChanged(const WriteStallInfo& wsi) override {
+		gorocksdb_writestallinfo_t info;
+		info.cf_name = str_data(wsi.cf_name);
+		info.cf_name_len = wsi.cf_name.size();
+		info.cur = write_stall_condition(wsi.condition.cur);
+		info.prev = write_stall_condition(wsi.condition.prev);
+		gorocksdb_eventlistener_on_stall_conditions_changed(idx_, &info);
+	}
+
+	void OnExternalFileIngested(DB* db, const ExternalFileIngestionInfo& efii) override {
+		gorocksdb_externalfileingestioninfo_t info;
+		info.cf_name = str_data(efii.cf_name);
+		info.cf_name_len = efii.cf_name.size();
+		info.external_file_path = str_data(efii.external_file_path);
+		info.external_file_path_len = efii.external_file_path.size();
+		info.internal_file_path = str_data(efii.internal_file_path);
+		info.internal_file_path_len = efii.internal_file_path.size();
+		info.global_seqno = efii.global_seqno;
+		gorocksdb_set_tableproperties(&info.table_properties, efii.table_properties);
+		gorocksdb_eventlistener_on_external_file_ingested(idx_, &info);
+	}
+
+private:
+	uintptr_t idx_;
+};
+
+// BackgroundErrorListener forwards the background errors to the
+// BackgroundErrorHandler registered in Go at idx.
+class BackgroundErrorListener : public EventListener {
+public:
+	explicit BackgroundErrorListener(uintptr_t idx) : idx_(idx) {}
+
+	void OnBackgroundError(BackgroundErrorReason reason, Status* bg_error) override {
+		std::string msg = bg_error->ToString();
+		unsigned char suppress = gorocksdb_backgrounderror_on_error(
+			idx_,
+			static_cast<int>(reason),
+			static_cast<int>(bg_error->severity()),
+			const_cast<char*>(msg.data()), msg.size());
+		if (suppress) {
+			*bg_error = Status::OK();
+		}
+	}
+
+	void OnErrorRecoveryCompleted(Status old_bg_error) override {
+		std::string msg = old_bg_error.ToString();
+		gorocksdb_backgrounderror_on_recovery_completed(
+			idx_,
+			static_cast<int>(old_bg_error.severity()),
+			const_cast<char*>(msg.data()), msg.size());
+	}
+
+private:
+	uintptr_t idx_;
+};
+
+extern "C" {
+
+
+void gorocksdb_options_add_eventlistener(rocksdb_options_t* opts, uintptr_t idx) {
+	opts->rep.listeners.push_back(std::make_shared<GoEventListener>(idx));
+}
+
+void gorocksdb_options_add_backgrounderror_listener(rocksdb_options_t* opts, uintptr_t idx) {
+	opts->rep.listeners.push_back(std::make_shared<BackgroundErrorListener>(idx));
+}
+
+
+}
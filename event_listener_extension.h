@@ -0,0 +1,113 @@
+#include "table_properties_extension.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+/* Event listener */
+
+typedef struct {
+	char* cf_name;
+	size_t cf_name_len;
+	char* file_path;
+	size_t file_path_len;
+	uint64_t thread_id;
+	int job_id;
+	unsigned char triggered_writes_slowdown;
+	unsigned char triggered_writes_stop;
+	uint64_t smallest_seqno;
+	uint64_t largest_seqno;
+	gorocksdb_tableproperties_t table_properties;
+} gorocksdb_flushjobinfo_t;
+
+typedef struct {
+	char* cf_name;
+	size_t cf_name_len;
+	char* status;
+	size_t status_len;
+	uint64_t thread_id;
+	int job_id;
+	int base_input_level;
+	int output_level;
+	char** input_files;
+	size_t* input_files_lens;
+	size_t num_input_files;
+	char** output_files;
+	size_t* output_files_lens;
+	size_t num_output_files;
+	int compaction_reason;
+	uint64_t elapsed_micros;
+	uint64_t num_input_records;
+	uint64_t num_output_records;
+	uint64_t total_input_bytes;
+	uint64_t total_output_bytes;
+} gorocksdb_compactionjobinfo_t;
+
+typedef struct {
+	char* db_name;
+	size_t db_name_len;
+	char* cf_name;
+	size_t cf_name_len;
+	char* file_path;
+	size_t file_path_len;
+	char* status;
+	size_t status_len;
+	int job_id;
+	int reason;
+	uint64_t file_size;
+	gorocksdb_tableproperties_t table_properties;
+} gorocksdb_tablefilecreationinfo_t;
+
+typedef struct {
+	char* db_name;
+	size_t db_name_len;
+	char* file_path;
+	size_t file_path_len;
+	char* status;
+	size_t status_len;
+	int job_id;
+} gorocksdb_tablefiledeletioninfo_t;
+
+typedef struct {
+	char* cf_name;
+	size_t cf_name_len;
+	int cur;
+	int prev;
+} gorocksdb_writestallinfo_t;
+
+typedef struct {
+	char* cf_name;
+	size_t cf_name_len;
+	char* external_file_path;
+	size_t external_file_path_len;
+	char* internal_file_path;
+	size_t internal_file_path_len;
+	uint64_t global_seqno;
+	gorocksdb_tableproperties_t table_properties;
+} gorocksdb_externalfileingestioninfo_t;
+
+// Implemented in Go.
+extern void gorocksdb_eventlistener_on_flush_completed(uintptr_t idx, gorocksdb_flushjobinfo_t* info);
+extern void gorocksdb_eventlistener_on_compaction_begin(uintptr_t idx, gorocksdb_compactionjobinfo_t* info);
+extern void gorocksdb_eventlistener_on_compaction_completed(uintptr_t idx, gorocksdb_compactionjobinfo_t* info);
+extern void gorocksdb_eventlistener_on_table_file_created(uintptr_t idx, gorocksdb_tablefilecreationinfo_t* info);
+extern void gorocksdb_eventlistener_on_table_file_deleted(uintptr_t idx, gorocksdb_tablefiledeletioninfo_t* info);
+extern void gorocksdb_eventlistener_on_stall_conditions_changed(uintptr_t idx, gorocksdb_writestallinfo_t* info);
+extern void gorocksdb_eventlistener_on_external_file_ingested(uintptr_t idx, gorocksdb_externalfileingestioninfo_t* info);
+
+void gorocksdb_options_add_eventlistener(rocksdb_options_t* opts, uintptr_t idx);
+
+/* Background errors */
+
+// Implemented in Go.
+extern unsigned char gorocksdb_backgrounderror_on_error(uintptr_t idx, int reason, int severity, char* msg, size_t msg_len);
+extern void gorocksdb_backgrounderror_on_recovery_completed(uintptr_t idx, int severity, char* msg, size_t msg_len);
+
+void gorocksdb_options_add_backgrounderror_listener(rocksdb_options_t* opts, uintptr_t idx);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -0,0 +1,130 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testEventListener struct {
+	EmptyEventListener
+	flushes     chan *FlushJobInfo
+	fileCreates chan *TableFileCreationInfo
+	compactions chan *CompactionJobInfo
+}
+
+func (l *testEventListener) OnFlushCompleted(info *FlushJobInfo) {
+	l.flushes <- info
+}
+
+func (l *testEventListener) OnTableFileCreated(info *TableFileCreationInfo) {
+	l.fileCreates <- info
+}
+
+func (l *testEventListener) OnCompactionCompleted(info *CompactionJobInfo) {
+	l.compactions <- info
+}
+
+func TestEventListener(t *testing.T) {
+	listener := &testEventListener{
+		flushes:     make(chan *FlushJobInfo, 16),
+		fileCreates: make(chan *TableFileCreationInfo, 16),
+		compactions: make(chan *CompactionJobInfo, 16),
+	}
+	db := newTestDB(t, "TestEventListener", func(opts *Options) {
+		opts.AddEventListener(listener)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.Put(wo, []byte("key"), []byte("value")))
+		require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	}
+
+	select {
+	case info := <-listener.flushes:
+		require.Equal(t, "default", info.ColumnFamilyName)
+		require.NotEmpty(t, info.FilePath)
+		require.Equal(t, uint64(1), info.TableProperties.NumEntries)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no flush event")
+	}
+
+	select {
+	case info := <-listener.fileCreates:
+		require.NoError(t, info.Err)
+		require.Equal(t, TableFileCreationReasonFlush, info.Reason)
+		require.True(t, info.FileSize > 0)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no table file creation event")
+	}
+
+	db.CompactRange(Range{nil, nil})
+	select {
+	case info := <-listener.compactions:
+		require.NoError(t, info.Err)
+		require.Len(t, info.InputFiles, 2)
+		require.Equal(t, CompactionReasonManualCompaction, info.Reason)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no compaction event")
+	}
+}
+
+type slowFlushListener struct {
+	EmptyEventListener
+	flushes int32
+}
+
+func (l *slowFlushListener) OnFlushCompleted(info *FlushJobInfo) {
+	time.Sleep(10 * time.Millisecond)
+	atomic.AddInt32(&l.flushes, 1)
+}
+
+func TestEventListenerClose(t *testing.T) {
+	listener := &slowFlushListener{}
+	db := newTestDB(t, "TestEventListenerClose", func(opts *Options) {
+		opts.AddEventListener(listener)
+	})
+	require.Len(t, db.listeners.queues, 1)
+	q := db.listeners.queues[0]
+
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, db.Put(wo, []byte("key"), []byte("value")))
+		require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	}
+
+	// Close delivers the queued events and stops the queue.
+	db.Close()
+	require.Equal(t, int32(5), atomic.LoadInt32(&listener.flushes))
+	select {
+	case <-q.done:
+	default:
+		t.Fatal("the queue was not stopped")
+	}
+	require.Nil(t, eventListenerQueues[q.idx])
+	require.Equal(t, uint64(0), db.DroppedEventCount())
+}
+
+func TestEventListenerQueueDrop(t *testing.T) {
+	q := newEventListenerQueue(EmptyEventListener{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	q.push(func(EventListener) {
+		close(started)
+		<-release
+	})
+	<-started
+
+	delivered := 0
+	for i := 0; i < maxEventListenerQueueLen+1; i++ {
+		q.push(func(EventListener) { delivered++ })
+	}
+	require.Equal(t, uint64(1), q.droppedEvents())
+
+	close(release)
+	q.close()
+	require.Equal(t, maxEventListenerQueueLen, delivered)
+}
@@ -0,0 +1,171 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "export_import_extension.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// ExportedFileMetaData describes an sst file of an exported column family.
+type ExportedFileMetaData struct {
+	// RelativeFilename is the name of the file in Directory.
+	RelativeFilename string `json:"relative_filename"`
+	Directory        string `json:"directory"`
+	ColumnFamilyName string `json:"column_family_name"`
+	Level            int    `json:"level"`
+	Size             uint64 `json:"size"`
+	SmallestKey      []byte `json:"smallest_key"`
+	LargestKey       []byte `json:"largest_key"`
+	SmallestSeqno    uint64 `json:"smallest_seqno"`
+	LargestSeqno     uint64 `json:"largest_seqno"`
+	NumEntries       uint64 `json:"num_entries"`
+	NumDeletions     uint64 `json:"num_deletions"`
+}
+
+// ExportImportFilesMetaData describes the files of an exported column family,
+// it can be serialized with encoding/json.
+type ExportImportFilesMetaData struct {
+	DbComparatorName string                 `json:"db_comparator_name"`
+	Files            []ExportedFileMetaData `json:"files"`
+}
+
+// SetDirectory sets the directory of all files,
+// for example after the exported files were copied to another host.
+func (m *ExportImportFilesMetaData) SetDirectory(dir string) {
+	for i := range m.Files {
+		m.Files[i].Directory = dir
+	}
+}
+
+// ExportColumnFamily exports all live sst files of the column family cf
+// to exportDir, which must not exist. The files are hard-linked if possible
+// and copied otherwise.
+// The returned metadata is required to import the column family with
+// CreateColumnFamilyWithImport.
+func (checkpoint *Checkpoint) ExportColumnFamily(cf *ColumnFamilyHandle, exportDir string) (*ExportImportFilesMetaData, error) {
+	var (
+		cErr               *C.char
+		cComparatorName    *C.char
+		cComparatorNameLen C.size_t
+		cFiles             *C.gorocksdb_exported_file_t
+		cNumFiles          C.size_t
+		cExportDir         = C.CString(exportDir)
+	)
+	defer C.free(unsafe.Pointer(cExportDir))
+
+	C.gorocksdb_checkpoint_export_column_family(checkpoint.c, cf.c, cExportDir,
+		&cComparatorName, &cComparatorNameLen, &cFiles, &cNumFiles, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_exported_files_destroy(cComparatorName, cFiles, cNumFiles)
+
+	num := int(cNumFiles)
+	metadata := &ExportImportFilesMetaData{
+		DbComparatorName: charToString(cComparatorName, cComparatorNameLen),
+		Files:            make([]ExportedFileMetaData, num),
+	}
+	if num == 0 {
+		return metadata, nil
+	}
+	cFileSlice := (*[1 << 30]C.gorocksdb_exported_file_t)(unsafe.Pointer(cFiles))[:num:num]
+	for i, cFile := range cFileSlice {
+		metadata.Files[i] = ExportedFileMetaData{
+			RelativeFilename: charToString(cFile.relative_filename, cFile.relative_filename_len),
+			Directory:        charToString(cFile.directory, cFile.directory_len),
+			ColumnFamilyName: charToString(cFile.column_family_name, cFile.column_family_name_len),
+			Level:            int(cFile.level),
+			Size:             uint64(cFile.size),
+			SmallestKey:      C.GoBytes(unsafe.Pointer(cFile.smallest_key), C.int(cFile.smallest_key_len)),
+			LargestKey:       C.GoBytes(unsafe.Pointer(cFile.largest_key), C.int(cFile.largest_key_len)),
+			SmallestSeqno:    uint64(cFile.smallest_seqno),
+			LargestSeqno:     uint64(cFile.largest_seqno),
+			NumEntries:       uint64(cFile.num_entries),
+			NumDeletions:     uint64(cFile.num_deletions),
+		}
+	}
+	return metadata, nil
+}
+
+// ImportColumnFamilyOptions represents the options of CreateColumnFamilyWithImport.
+type ImportColumnFamilyOptions struct {
+	c *C.gorocksdb_import_column_family_options_t
+}
+
+// NewDefaultImportColumnFamilyOptions creates a default ImportColumnFamilyOptions object.
+func NewDefaultImportColumnFamilyOptions() *ImportColumnFamilyOptions {
+	return &ImportColumnFamilyOptions{c: C.gorocksdb_import_column_family_options_create()}
+}
+
+// SetMoveFiles specifies if the files are moved instead of copied.
+// Default: false
+func (opts *ImportColumnFamilyOptions) SetMoveFiles(value bool) {
+	C.gorocksdb_import_column_family_options_set_move_files(opts.c, boolToChar(value))
+}
+
+// Destroy deallocates the ImportColumnFamilyOptions object.
+func (opts *ImportColumnFamilyOptions) Destroy() {
+	C.gorocksdb_import_column_family_options_destroy(opts.c)
+	opts.c = nil
+}
+
+// CreateColumnFamilyWithImport creates the column family name with the files
+// of an exported column family, without rewriting the data.
+// The comparator of opts has to match metadata.DbComparatorName.
+func (db *DB) CreateColumnFamilyWithImport(
+	opts *Options,
+	name string,
+	importOpts *ImportColumnFamilyOptions,
+	metadata *ExportImportFilesMetaData,
+) (*ColumnFamilyHandle, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cComparatorName := []byte(metadata.DbComparatorName)
+
+	// the files are passed in C memory as they contain pointers.
+	var cStrings []unsafe.Pointer
+	defer func() {
+		for _, s := range cStrings {
+			C.free(s)
+		}
+	}()
+	cBytes := func(b []byte) (*C.char, C.size_t) {
+		c := C.CBytes(b)
+		cStrings = append(cStrings, c)
+		return (*C.char)(c), C.size_t(len(b))
+	}
+	num := len(metadata.Files)
+	var cFiles *C.gorocksdb_exported_file_t
+	if num > 0 {
+		cFiles = (*C.gorocksdb_exported_file_t)(C.calloc(C.size_t(num), C.size_t(unsafe.Sizeof(*cFiles))))
+		defer C.free(unsafe.Pointer(cFiles))
+		cFileSlice := (*[1 << 30]C.gorocksdb_exported_file_t)(unsafe.Pointer(cFiles))[:num:num]
+		for i, file := range metadata.Files {
+			cFile := &cFileSlice[i]
+			cFile.relative_filename, cFile.relative_filename_len = cBytes([]byte(file.RelativeFilename))
+			cFile.directory, cFile.directory_len = cBytes([]byte(file.Directory))
+			cFile.column_family_name, cFile.column_family_name_len = cBytes([]byte(file.ColumnFamilyName))
+			cFile.level = C.int(file.Level)
+			cFile.size = C.uint64_t(file.Size)
+			cFile.smallest_key, cFile.smallest_key_len = cBytes(file.SmallestKey)
+			cFile.largest_key, cFile.largest_key_len = cBytes(file.LargestKey)
+			cFile.smallest_seqno = C.uint64_t(file.SmallestSeqno)
+			cFile.largest_seqno = C.uint64_t(file.LargestSeqno)
+			cFile.num_entries = C.uint64_t(file.NumEntries)
+			cFile.num_deletions = C.uint64_t(file.NumDeletions)
+		}
+	}
+
+	var cErr *C.char
+	cHandle := C.gorocksdb_create_column_family_with_import(db.c, opts.c, cName, importOpts.c,
+		byteToChar(cComparatorName), C.size_t(len(cComparatorName)), cFiles, C.size_t(num), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	return NewNativeColumnFamilyHandle(cHandle), nil
+}
@@ -0,0 +1,123 @@
+#include "export_import_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include <string>
+#include "rocksdb/metadata.h"
+#include "rocksdb_internal.h"
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::ColumnFamilyOptions;
+using rocksdb::ExportImportFilesMetaData;
+using rocksdb::ImportColumnFamilyOptions;
+using rocksdb::LiveFileMetaData;
+
+struct gorocksdb_import_column_family_options_t {
+	ImportColumnFamilyOptions rep;
+};
+
+static void copy_string(const std::string& s, char** c, size_t* len) {
+	*c = static_cast<char*>(malloc(s.size()));
+	memcpy(*c, s.data(), s.size());
+	*len = s.size();
+}
+
+extern "C" {
+
+
+void gorocksdb_checkpoint_export_column_family(
+	rocksdb_checkpoint_t* checkpoint, rocksdb_column_family_handle_t* column_family,
+	const char* export_dir, char** db_comparator_name, size_t* db_comparator_name_len,
+	gorocksdb_exported_file_t** files, size_t* num_files, char** errptr) {
+
+	ExportImportFilesMetaData* metadata = NULL;
+	if (SaveError(errptr, checkpoint->rep->ExportColumnFamily(column_family->rep, export_dir, &metadata))) {
+		return;
+	}
+
+	copy_string(metadata->db_comparator_name, db_comparator_name, db_comparator_name_len);
+	*num_files = metadata->files.size();
+	*files = static_cast<gorocksdb_exported_file_t*>(calloc(metadata->files.size(), sizeof(gorocksdb_exported_file_t)));
+	for (size_t i = 0; i < metadata->files.size(); i++) {
+		const LiveFileMetaData& src = metadata->files[i];
+		gorocksdb_exported_file_t* dst = &(*files)[i];
+		copy_string(src.relative_filename, &dst->relative_filename, &dst->relative_filename_len);
+		copy_string(src.directory, &dst->directory, &dst->directory_len);
+		copy_string(src.column_family_name, &dst->column_family_name, &dst->column_family_name_len);
+		dst->level = src.level;
+		dst->size = src.size;
+		copy_string(src.smallestkey, &dst->smallest_key, &dst->smallest_key_len);
+		copy_string(src.largestkey, &dst->largest_key, &dst->largest_key_len);
+		dst->smallest_seqno = src.smallest_seqno;
+		dst->largest_seqno = src.largest_seqno;
+		dst->num_entries = src.num_entries;
+		dst->num_deletions = src.num_deletions;
+	}
+	delete metadata;
+}
+
+void gorocksdb_exported_files_destroy(char* db_comparator_name, gorocksdb_exported_file_t* files, size_t num_files) {
+	free(db_comparator_name);
+	for (size_t i = 0; i < num_files; i++) {
+		free(files[i].relative_filename);
+		free(files[i].directory);
+		free(files[i].column_family_name);
+		free(files[i].smallest_key);
+		free(files[i].largest_key);
+	}
+	free(files);
+}
+
+gorocksdb_import_column_family_options_t* gorocksdb_import_column_family_options_create() {
+	return new gorocksdb_import_column_family_options_t;
+}
+
+void gorocksdb_import_column_family_options_set_move_files(gorocksdb_import_column_family_options_t* opts, unsigned char v) {
+	opts->rep.move_files = v;
+}
+
+void gorocksdb_import_column_family_options_destroy(gorocksdb_import_column_family_options_t* opts) {
+	delete opts;
+}
+
+rocksdb_column_family_handle_t* gorocksdb_create_column_family_with_import(
+	rocksdb_t* db, const rocksdb_options_t* column_family_options, const char* column_family_name,
+	const gorocksdb_import_column_family_options_t* import_options,
+	const char* db_comparator_name, size_t db_comparator_name_len,
+	const gorocksdb_exported_file_t* files, size_t num_files, char** errptr) {
+
+	ExportImportFilesMetaData metadata;
+	metadata.db_comparator_name.assign(db_comparator_name, db_comparator_name_len);
+	metadata.files.resize(num_files);
+	for (size_t i = 0; i < num_files; i++) {
+		const gorocksdb_exported_file_t* src = &files[i];
+		LiveFileMetaData& dst = metadata.files[i];
+		dst.relative_filename.assign(src->relative_filename, src->relative_filename_len);
+		dst.directory.assign(src->directory, src->directory_len);
+		// the deprecated names are still used by some rocksdb versions.
+		dst.name = "/" + dst.relative_filename;
+		dst.db_path = dst.directory;
+		dst.column_family_name.assign(src->column_family_name, src->column_family_name_len);
+		dst.level = src->level;
+		dst.size = src->size;
+		dst.smallestkey.assign(src->smallest_key, src->smallest_key_len);
+		dst.largestkey.assign(src->largest_key, src->largest_key_len);
+		dst.smallest_seqno = src->smallest_seqno;
+		dst.largest_seqno = src->largest_seqno;
+		dst.num_entries = src->num_entries;
+		dst.num_deletions = src->num_deletions;
+	}
+
+	ColumnFamilyHandle* handle = NULL;
+	if (SaveError(errptr, db->rep->CreateColumnFamilyWithImport(
+			ColumnFamilyOptions(column_family_options->rep), column_family_name,
+			import_options->rep, metadata, &handle))) {
+		return NULL;
+	}
+	rocksdb_column_family_handle_t* c_handle = new rocksdb_column_family_handle_t;
+	c_handle->rep = handle;
+	return c_handle;
+}
+
+
+}
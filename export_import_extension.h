@@ -0,0 +1,49 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+typedef struct {
+	char* relative_filename;
+	size_t relative_filename_len;
+	char* directory;
+	size_t directory_len;
+	char* column_family_name;
+	size_t column_family_name_len;
+	int level;
+	uint64_t size;
+	char* smallest_key;
+	size_t smallest_key_len;
+	char* largest_key;
+	size_t largest_key_len;
+	uint64_t smallest_seqno;
+	uint64_t largest_seqno;
+	uint64_t num_entries;
+	uint64_t num_deletions;
+} gorocksdb_exported_file_t;
+
+typedef struct gorocksdb_import_column_family_options_t gorocksdb_import_column_family_options_t;
+
+// The comparator name and the files have to be freed with gorocksdb_exported_files_destroy.
+void gorocksdb_checkpoint_export_column_family(
+	rocksdb_checkpoint_t* checkpoint, rocksdb_column_family_handle_t* column_family,
+	const char* export_dir, char** db_comparator_name, size_t* db_comparator_name_len,
+	gorocksdb_exported_file_t** files, size_t* num_files, char** errptr);
+
+void gorocksdb_exported_files_destroy(char* db_comparator_name, gorocksdb_exported_file_t* files, size_t num_files);
+
+gorocksdb_import_column_family_options_t* gorocksdb_import_column_family_options_create();
+void gorocksdb_import_column_family_options_set_move_files(gorocksdb_import_column_family_options_t* opts, unsigned char v);
+void gorocksdb_import_column_family_options_destroy(gorocksdb_import_column_family_options_t* opts);
+
+rocksdb_column_family_handle_t* gorocksdb_create_column_family_with_import(
+	rocksdb_t* db, const rocksdb_options_t* column_family_options, const char* column_family_name,
+	const gorocksdb_import_column_family_options_t* import_options,
+	const char* db_comparator_name, size_t db_comparator_name_len,
+	const gorocksdb_exported_file_t* files, size_t num_files, char** errptr);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -0,0 +1,59 @@
+package gorocksdb
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportImportColumnFamily(t *testing.T) {
+	db, cfs := newTestDBCFs(t, "TestExportImportColumnFamily", []string{"default", "tenant"}, nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	givenKeys := []string{"key1", "key2", "key3"}
+	for _, key := range givenKeys {
+		require.NoError(t, db.PutCF(wo, cfs[1], []byte(key), []byte(key+"Value")))
+	}
+
+	exportDir, err := ioutil.TempDir("", "gorocksdb-TestExportImportColumnFamily-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(exportDir)
+	exportDir = filepath.Join(exportDir, "tenant")
+
+	checkpoint, err := db.NewCheckpoint()
+	require.NoError(t, err)
+	defer checkpoint.Destroy()
+	metadata, err := checkpoint.ExportColumnFamily(cfs[1], exportDir)
+	require.NoError(t, err)
+	require.Equal(t, "leveldb.BytewiseComparator", metadata.DbComparatorName)
+	require.NotEmpty(t, metadata.Files)
+	require.Equal(t, "tenant", metadata.Files[0].ColumnFamilyName)
+
+	data, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	var imported ExportImportFilesMetaData
+	require.NoError(t, json.Unmarshal(data, &imported))
+	require.Equal(t, *metadata, imported)
+
+	dbImport := newTestDB(t, "TestExportImportColumnFamily-import", nil)
+	defer dbImport.Close()
+
+	importOpts := NewDefaultImportColumnFamilyOptions()
+	defer importOpts.Destroy()
+	importOpts.SetMoveFiles(false)
+	cf, err := dbImport.CreateColumnFamilyWithImport(NewDefaultOptions(), "tenant", importOpts, &imported)
+	require.NoError(t, err)
+	defer cf.Destroy()
+
+	ro := NewDefaultReadOptions()
+	for _, key := range givenKeys {
+		v, err := dbImport.GetCF(ro, cf, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, []byte(key+"Value"), v)
+		CfreeByteSlice(v)
+	}
+}
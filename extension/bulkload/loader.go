@@ -0,0 +1,317 @@
+// Package bulkload loads unsorted key value pairs into a column family
+// by sorting them on disk, writing SST files in parallel and ingesting
+// them with IngestExternalFileCF.
+package bulkload
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/kapitan-k/gorocksdb"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// bytewiseComparatorName is the name of the comparator rocksdb uses by default,
+// which orders like bytes.Compare.
+const bytewiseComparatorName = "leveldb.BytewiseComparator"
+
+// ErrFinished is returned by Add and Finish if the Loader was finished or closed.
+var ErrFinished = errors.New("bulkload: loader is finished")
+
+// Options configures a Loader.
+type Options struct {
+	// Dir is the directory the temporary files are created in.
+	// Default: os.TempDir()
+	Dir string
+	// Comparator orders the keys, it has to be the comparator
+	// of the column family. New fails if its name differs from the
+	// name of the comparator of the column family options.
+	// Default: bytewise ordering, which is leveldb.BytewiseComparator
+	Comparator gorocksdb.Comparator
+	// BufferSize is the number of bytes of added keys and values
+	// which are buffered in memory before they are sorted and written
+	// to a temporary file.
+	// Default: 64MB
+	BufferSize int
+	// TargetFileSize is the approximate size of the data of each SST file.
+	// Up to Parallelism files are buffered in memory while they are written.
+	// Default: 64MB
+	TargetFileSize int
+	// Parallelism is the number of SST files written concurrently.
+	// Default: runtime.NumCPU()
+	Parallelism int
+}
+
+// Loader collects key value pairs in any order and ingests them
+// into a column family. Of equal keys, the value added last is loaded.
+// Add is safe for concurrent use.
+type Loader struct {
+	db      *gorocksdb.DB
+	cf      *gorocksdb.ColumnFamilyHandle
+	cfOpts  *gorocksdb.Options
+	opts    Options
+	compare func(a, b []byte) int
+	dir     string
+
+	mu         sync.Mutex
+	entries    []entry
+	size       int
+	runs       []string
+	spills     sync.WaitGroup
+	spillErr   error
+	finished   bool
+	numEntries uint64
+}
+
+// New creates a Loader for the column family cf of db,
+// cfOpts are the options cf was opened with. opts may be nil.
+// It returns an error if the comparator of opts does not match the
+// comparator of cfOpts, the SST files would be rejected otherwise.
+// The Loader has to be closed to remove its temporary files.
+func New(db *gorocksdb.DB, cf *gorocksdb.ColumnFamilyHandle, cfOpts *gorocksdb.Options, opts *Options) (*Loader, error) {
+	l := &Loader{
+		db:      db,
+		cf:      cf,
+		cfOpts:  cfOpts,
+		compare: bytes.Compare,
+	}
+	if opts != nil {
+		l.opts = *opts
+	}
+	cmpName := bytewiseComparatorName
+	if l.opts.Comparator != nil {
+		l.compare = l.opts.Comparator.Compare
+		cmpName = l.opts.Comparator.Name()
+	}
+	if cfCmpName := cfOpts.GetComparatorName(); cmpName != cfCmpName {
+		return nil, fmt.Errorf("bulkload: comparator %s does not match the comparator %s of the column family", cmpName, cfCmpName)
+	}
+	if l.opts.BufferSize <= 0 {
+		l.opts.BufferSize = 64 << 20
+	}
+	if l.opts.TargetFileSize <= 0 {
+		l.opts.TargetFileSize = 64 << 20
+	}
+	if l.opts.Parallelism <= 0 {
+		l.opts.Parallelism = runtime.NumCPU()
+	}
+
+	dir, err := ioutil.TempDir(l.opts.Dir, "gorocksdb-bulkload")
+	if err != nil {
+		return nil, err
+	}
+	l.dir = dir
+	return l, nil
+}
+
+// Add adds a copy of key and value.
+func (l *Loader) Add(key, value []byte) error {
+	e := entry{
+		key:   append([]byte(nil), key...),
+		value: append([]byte(nil), value...),
+	}
+
+	l.mu.Lock()
+	if l.finished {
+		l.mu.Unlock()
+		return ErrFinished
+	}
+	if l.spillErr != nil {
+		err := l.spillErr
+		l.mu.Unlock()
+		return err
+	}
+	l.entries = append(l.entries, e)
+	l.size += len(key) + len(value)
+	l.numEntries++
+	if l.size < l.opts.BufferSize {
+		l.mu.Unlock()
+		return nil
+	}
+	entries, path := l.takeRun()
+	l.mu.Unlock()
+
+	// the run is written without the lock, so other goroutines can continue to add.
+	if err := writeRun(path, entries, l.compare); err != nil {
+		l.setSpillErr(err)
+	}
+	l.spills.Done()
+	return l.err()
+}
+
+// takeRun returns the buffered entries and the path of their run.
+// REQUIRES: l.mu is held.
+func (l *Loader) takeRun() ([]entry, string) {
+	entries := l.entries
+	path := filepath.Join(l.dir, fmt.Sprintf("run-%06d", len(l.runs)))
+	l.runs = append(l.runs, path)
+	l.entries = nil
+	l.size = 0
+	l.spills.Add(1)
+	return entries, path
+}
+
+func (l *Loader) setSpillErr(err error) {
+	l.mu.Lock()
+	if l.spillErr == nil {
+		l.spillErr = err
+	}
+	l.mu.Unlock()
+}
+
+func (l *Loader) err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.spillErr
+}
+
+// NumEntries returns the number of added entries, including duplicate keys.
+func (l *Loader) NumEntries() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.numEntries
+}
+
+// Finish sorts the added entries, writes them to SST files and ingests
+// these atomically into the column family. ingestOpts may be nil, then
+// the files are moved into the DB.
+// All calls to Add have to return before Finish is called.
+func (l *Loader) Finish(ingestOpts *gorocksdb.IngestExternalFileOptions) error {
+	l.mu.Lock()
+	if l.finished {
+		l.mu.Unlock()
+		return ErrFinished
+	}
+	l.finished = true
+	var (
+		entries []entry
+		path    string
+	)
+	if len(l.entries) > 0 {
+		entries, path = l.takeRun()
+	}
+	l.mu.Unlock()
+
+	if path != "" {
+		if err := writeRun(path, entries, l.compare); err != nil {
+			l.setSpillErr(err)
+		}
+		l.spills.Done()
+	}
+	l.spills.Wait()
+	if err := l.err(); err != nil {
+		return err
+	}
+
+	files, err := l.writeFiles()
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	if ingestOpts == nil {
+		ingestOpts = gorocksdb.NewDefaultIngestExternalFileOptions()
+		defer ingestOpts.Destroy()
+		ingestOpts.SetMoveFiles(true)
+	}
+	return l.db.IngestExternalFileCF(l.cf, files, ingestOpts)
+}
+
+// sstChunk is the sorted data of one SST file.
+type sstChunk struct {
+	path    string
+	entries []entry
+}
+
+// writeFiles merges the runs and writes the non-overlapping SST files in parallel.
+func (l *Loader) writeFiles() ([]string, error) {
+	chunks := make(chan sstChunk)
+	errs := make(chan error, l.opts.Parallelism)
+	var workers sync.WaitGroup
+	for i := 0; i < l.opts.Parallelism; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			envOpts := gorocksdb.NewDefaultEnvOptions()
+			defer envOpts.Destroy()
+			for chunk := range chunks {
+				if err := l.writeFile(envOpts, chunk); err != nil {
+					errs <- err
+					// drain the remaining chunks.
+					for range chunks {
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	var (
+		files []string
+		chunk sstChunk
+		size  int
+	)
+	send := func() error {
+		chunk.path = filepath.Join(l.dir, fmt.Sprintf("%06d.sst", len(files)))
+		files = append(files, chunk.path)
+		select {
+		case chunks <- chunk:
+		case err := <-errs:
+			return err
+		}
+		chunk = sstChunk{}
+		size = 0
+		return nil
+	}
+	err := mergeRuns(l.runs, l.compare, func(e entry) error {
+		chunk.entries = append(chunk.entries, e)
+		size += len(e.key) + len(e.value)
+		if size >= l.opts.TargetFileSize {
+			return send()
+		}
+		return nil
+	})
+	if err == nil && len(chunk.entries) > 0 {
+		err = send()
+	}
+	close(chunks)
+	workers.Wait()
+	close(errs)
+
+	if err != nil {
+		return nil, err
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (l *Loader) writeFile(envOpts *gorocksdb.EnvOptions, chunk sstChunk) error {
+	w := gorocksdb.NewSSTFileWriterCF(envOpts, l.cfOpts, l.cf)
+	defer w.Destroy()
+	if err := w.Open(chunk.path); err != nil {
+		return err
+	}
+	for _, e := range chunk.entries {
+		if err := w.Put(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	_, err := w.Finish()
+	return err
+}
+
+// Close removes the temporary files. Entries which were not
+// ingested with Finish are discarded.
+func (l *Loader) Close() error {
+	l.mu.Lock()
+	l.finished = true
+	l.entries = nil
+	l.mu.Unlock()
+	l.spills.Wait()
+	return os.RemoveAll(l.dir)
+}
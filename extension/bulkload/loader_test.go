@@ -0,0 +1,138 @@
+package bulkload
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kapitan-k/gorocksdb"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+)
+
+func TestLoader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestLoader")
+	require.NoError(t, err)
+
+	opts := gorocksdb.NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(true)
+	opts.SetCreateIfMissingColumnFamilies(true)
+
+	db, cfs, err := gorocksdb.OpenDbColumnFamilies(opts, dir,
+		[]string{"default", "bulk"}, []*gorocksdb.Options{opts, opts})
+	require.NoError(t, err)
+	defer db.Close()
+
+	l, err := New(db, cfs[1], opts, &Options{
+		BufferSize:     1 << 10,
+		TargetFileSize: 4 << 10,
+		Parallelism:    3,
+	})
+	require.NoError(t, err)
+	defer l.Close()
+
+	const (
+		numWorkers = 4
+		numKeys    = 1000
+	)
+	errs := make(chan error, numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func(w int) {
+			for _, i := range rand.Perm(numKeys) {
+				if i%numWorkers != w {
+					continue
+				}
+				key := []byte(fmt.Sprintf("key%04d", i))
+				if err := l.Add(key, []byte("old")); err != nil {
+					errs <- err
+					return
+				}
+				if err := l.Add(key, key); err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}(w)
+	}
+	for w := 0; w < numWorkers; w++ {
+		require.NoError(t, <-errs)
+	}
+	require.Equal(t, uint64(2*numKeys), l.NumEntries())
+
+	require.NoError(t, l.Finish(nil))
+	require.Equal(t, ErrFinished, l.Finish(nil))
+	require.Equal(t, ErrFinished, l.Add([]byte("key"), nil))
+
+	numFiles, ok := db.GetIntPropertyCF(gorocksdb.PropertyNumFilesAtLevel(6), cfs[1])
+	require.True(t, ok)
+	require.True(t, numFiles > 1)
+
+	itr := db.NewIteratorCF(gorocksdb.NewDefaultReadOptions(), cfs[1])
+	defer itr.Close()
+	i := 0
+	for itr.SeekToFirst(); itr.Valid(); itr.Next() {
+		key := fmt.Sprintf("key%04d", i)
+		require.Equal(t, key, string(itr.Key()))
+		require.Equal(t, key, string(itr.Value()))
+		i++
+	}
+	require.NoError(t, itr.Err())
+	require.Equal(t, numKeys, i)
+}
+
+type reverseComparator struct{}
+
+func (reverseComparator) Compare(a, b []byte) int { return bytes.Compare(b, a) }
+func (reverseComparator) Name() string            { return "gorocksdb.test.reverse" }
+
+func TestLoaderComparator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestLoaderComparator")
+	require.NoError(t, err)
+
+	opts := gorocksdb.NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(true)
+	opts.SetCreateIfMissingColumnFamilies(true)
+	cfOpts := gorocksdb.NewDefaultOptions()
+	defer cfOpts.Destroy()
+	cfOpts.SetComparator(reverseComparator{})
+
+	db, cfs, err := gorocksdb.OpenDbColumnFamilies(opts, dir,
+		[]string{"default", "bulk"}, []*gorocksdb.Options{opts, cfOpts})
+	require.NoError(t, err)
+	defer db.Close()
+
+	// the bytewise default does not match the comparator of the column family.
+	_, err = New(db, cfs[1], cfOpts, nil)
+	require.Error(t, err)
+
+	l, err := New(db, cfs[1], cfOpts, &Options{
+		Comparator:     reverseComparator{},
+		BufferSize:     1 << 10,
+		TargetFileSize: 4 << 10,
+		Parallelism:    2,
+	})
+	require.NoError(t, err)
+	defer l.Close()
+
+	const numKeys = 500
+	for _, i := range rand.Perm(numKeys) {
+		key := []byte(fmt.Sprintf("key%04d", i))
+		require.NoError(t, l.Add(key, key))
+	}
+	require.NoError(t, l.Finish(nil))
+
+	itr := db.NewIteratorCF(gorocksdb.NewDefaultReadOptions(), cfs[1])
+	defer itr.Close()
+	i := numKeys
+	for itr.SeekToFirst(); itr.Valid(); itr.Next() {
+		i--
+		key := fmt.Sprintf("key%04d", i)
+		require.Equal(t, key, string(itr.Key()))
+		require.Equal(t, key, string(itr.Value()))
+	}
+	require.NoError(t, itr.Err())
+	require.Equal(t, 0, i)
+}
@@ -0,0 +1,172 @@
+package bulkload
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+)
+
+type entry struct {
+	key, value []byte
+}
+
+// writeRun sorts entries stable by key and writes them to a new file at path.
+// Of equal keys only the last added entry is written.
+func writeRun(path string, entries []entry, compare func(a, b []byte) int) error {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return compare(entries[i].key, entries[j].key) < 0
+	})
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	var buf [binary.MaxVarintLen64]byte
+	for i, e := range entries {
+		if i+1 < len(entries) && compare(e.key, entries[i+1].key) == 0 {
+			continue
+		}
+		for _, b := range [][]byte{e.key, e.value} {
+			n := binary.PutUvarint(buf[:], uint64(len(b)))
+			if _, err := w.Write(buf[:n]); err != nil {
+				f.Close()
+				return err
+			}
+			if _, err := w.Write(b); err != nil {
+				f.Close()
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runReader reads the sorted entries of a run file.
+type runReader struct {
+	f     *os.File
+	r     *bufio.Reader
+	index int
+	cur   entry
+}
+
+func openRun(path string, index int) (*runReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &runReader{f: f, r: bufio.NewReader(f), index: index}, nil
+}
+
+// next reads the next entry, it returns io.EOF at the end of the run.
+func (r *runReader) next() error {
+	key, err := r.readBytes()
+	if err != nil {
+		return err
+	}
+	value, err := r.readBytes()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	r.cur = entry{key, value}
+	return nil
+}
+
+func (r *runReader) readBytes() ([]byte, error) {
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *runReader) close() error {
+	return r.f.Close()
+}
+
+// runHeap orders the runs by their current key,
+// of equal keys the entry of the later run comes first.
+type runHeap struct {
+	runs    []*runReader
+	compare func(a, b []byte) int
+}
+
+func (h *runHeap) Len() int { return len(h.runs) }
+func (h *runHeap) Less(i, j int) bool {
+	if c := h.compare(h.runs[i].cur.key, h.runs[j].cur.key); c != 0 {
+		return c < 0
+	}
+	return h.runs[i].index > h.runs[j].index
+}
+func (h *runHeap) Swap(i, j int)      { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+func (h *runHeap) Push(x interface{}) { h.runs = append(h.runs, x.(*runReader)) }
+func (h *runHeap) Pop() interface{} {
+	n := len(h.runs)
+	r := h.runs[n-1]
+	h.runs = h.runs[:n-1]
+	return r
+}
+
+// mergeRuns merges the sorted runs at paths and calls fn with the entries
+// in order. Of equal keys only the entry of the latest run is passed.
+func mergeRuns(paths []string, compare func(a, b []byte) int, fn func(e entry) error) (err error) {
+	h := &runHeap{compare: compare}
+	defer func() {
+		for _, r := range h.runs {
+			r.close()
+		}
+	}()
+	for i, path := range paths {
+		r, err := openRun(path, i)
+		if err != nil {
+			return err
+		}
+		if err := r.next(); err == io.EOF {
+			r.close()
+			continue
+		} else if err != nil {
+			r.close()
+			return err
+		}
+		h.runs = append(h.runs, r)
+	}
+	heap.Init(h)
+
+	var last []byte
+	for h.Len() > 0 {
+		r := h.runs[0]
+		if last == nil || compare(last, r.cur.key) != 0 {
+			last = r.cur.key
+			if err := fn(r.cur); err != nil {
+				return err
+			}
+		}
+
+		if err := r.next(); err == io.EOF {
+			heap.Pop(h)
+			r.close()
+		} else if err != nil {
+			return err
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return nil
+}
@@ -19,10 +19,14 @@ func OptionsSetSingleUint64Comparator(
 	cmpPrefixByteOffset uint64,
 	opts *gorocksdb.Options,
 ) {
-	opts.SetComparatorUnsafe(
-		unsafe.Pointer(C.singleuint64comparator_new(
-			C.size_t(cmpPrefixByteOffset))),
-	)
+	opts.SetComparatorUnsafe(NewSingleUint64ComparatorUnsafe(cmpPrefixByteOffset))
+}
+
+// NewSingleUint64ComparatorUnsafe returns a newly allocated
+// singleuint64comparator_t as a rocksdb_comparator_t.
+// It can be used with the comparator MultiIterator for example.
+func NewSingleUint64ComparatorUnsafe(cmpPrefixByteOffset uint64) unsafe.Pointer {
+	return unsafe.Pointer(C.singleuint64comparator_new(C.size_t(cmpPrefixByteOffset)))
 }
 
 // OptionsSetDoubleUint64Comparator sets a
@@ -33,10 +37,13 @@ func OptionsSetDoubleUint64Comparator(
 	cmpPrefixByteOffset uint64,
 	opts *gorocksdb.Options,
 ) {
-	opts.SetComparatorUnsafe(
-		unsafe.Pointer(C.doubleuint64comparator_new(
-			C.size_t(cmpPrefixByteOffset))),
-	)
+	opts.SetComparatorUnsafe(NewDoubleUint64ComparatorUnsafe(cmpPrefixByteOffset))
+}
+
+// NewDoubleUint64ComparatorUnsafe returns a newly allocated
+// doubleuint64comparator_t as a rocksdb_comparator_t.
+func NewDoubleUint64ComparatorUnsafe(cmpPrefixByteOffset uint64) unsafe.Pointer {
+	return unsafe.Pointer(C.doubleuint64comparator_new(C.size_t(cmpPrefixByteOffset)))
 }
 
 // OptionsSetReverseSingleUint64Comparator sets a
@@ -47,8 +54,11 @@ func OptionsSetReverseSingleUint64Comparator(
 	cmpPrefixByteOffset uint64,
 	opts *gorocksdb.Options,
 ) {
-	opts.SetComparatorUnsafe(
-		unsafe.Pointer(C.reversesingleuint64comparator_new(
-			C.size_t(cmpPrefixByteOffset))),
-	)
+	opts.SetComparatorUnsafe(NewReverseSingleUint64ComparatorUnsafe(cmpPrefixByteOffset))
+}
+
+// NewReverseSingleUint64ComparatorUnsafe returns a newly allocated
+// reversesingleuint64comparator_t as a rocksdb_comparator_t.
+func NewReverseSingleUint64ComparatorUnsafe(cmpPrefixByteOffset uint64) unsafe.Pointer {
+	return unsafe.Pointer(C.reversesingleuint64comparator_new(C.size_t(cmpPrefixByteOffset)))
 }
@@ -62,4 +62,21 @@ void iter_valid_next_to_buffer(
 }
 
 
+void iter_move(rocksdb_iterator_t* iter, const int64_t direction, size_t steps) {
+	for (size_t i = 0; i < steps; i++) {
+		if (!rocksdb_iter_valid(iter)) {
+			if (direction < 0) {
+				rocksdb_iter_seek_to_last(iter);
+			} else {
+				rocksdb_iter_seek_to_first(iter);
+			}
+		} else if (direction < 0) {
+			rocksdb_iter_prev(iter);
+		} else {
+			rocksdb_iter_next(iter);
+		}
+	}
+}
+
+
 }
\ No newline at end of file
@@ -26,6 +26,15 @@ var ErrInvalidIteratorDirection = errors.New("Invalid iterator direction")
 type GoBufferIterator struct {
 	bbi goiterator.BaseBufferIterator
 	itr *gorocksdb.Iterator
+
+	// asyncPrefetch enables filling prefetched on a background goroutine
+	// while bbi is consumed.
+	asyncPrefetch bool
+	prefetched    goiterator.BaseBufferIterator
+	// pending is non nil while a prefetch is in flight and
+	// is closed once the prefetch finished.
+	pending       chan struct{}
+	prefetchReady bool
 }
 
 // NewGoBufferIteratorFromIterator allocates a new GoBufferIterator
@@ -76,19 +85,95 @@ func NewGoBufferIteratorFromIteratorWithBuffer(
 	return
 }
 
+// SetAsyncPrefetch enables or disables asynchronous double-buffered prefetching.
+// If enabled, the next readahead buffer is filled on a background goroutine
+// while the current one is consumed, so Valid() does not stall on the cgo call
+// each time the buffer is exhausted. This doubles the memory used for buffering.
+// The underlying iterator must not be used directly while prefetching is enabled.
+func (gbi *GoBufferIterator) SetAsyncPrefetch(enable bool) {
+	gbi.discardPrefetch()
+	if enable && gbi.prefetched.Buffer == nil {
+		pbbi := &gbi.bbi
+		gbi.prefetched = goiterator.CreateBaseBufferIterator(
+			pbbi.ReadaheadSize, pbbi.ReadaheadCnt, pbbi.AutoAdjustBufferSize)
+	}
+	gbi.asyncPrefetch = enable
+}
+
 // UnderlyingItr returns the underlying Iterator.
+// If async prefetching is enabled it must not be moved directly.
 func (gbi *GoBufferIterator) UnderlyingItr() *gorocksdb.Iterator {
 	return gbi.itr
 }
 
 // Close closes the underlying Iterator.
+// An in flight prefetch is awaited before.
 func (gbi *GoBufferIterator) Close() {
+	gbi.discardPrefetch()
 	gbi.itr.Close()
 }
 
+// waitPrefetch blocks until an in flight prefetch has finished.
+func (gbi *GoBufferIterator) waitPrefetch() {
+	if gbi.pending == nil {
+		return
+	}
+	<-gbi.pending
+	gbi.pending = nil
+	gbi.prefetchReady = true
+}
+
+// discardPrefetch waits for an in flight prefetch and drops its data.
+// Must be called before the underlying iterator is repositioned.
+func (gbi *GoBufferIterator) discardPrefetch() {
+	gbi.waitPrefetch()
+	if gbi.prefetchReady {
+		gbi.prefetched.Reset()
+		gbi.prefetchReady = false
+	}
+}
+
+// startPrefetch fills prefetched from the underlying iterator on a background goroutine.
+func (gbi *GoBufferIterator) startPrefetch() {
+	pbbi := &gbi.bbi
+	ppre := &gbi.prefetched
+	if ppre.ReadaheadSize < pbbi.ReadaheadSize || ppre.ReadaheadCnt != pbbi.ReadaheadCnt {
+		ppre.SetReadahead(pbbi.ReadaheadSize, pbbi.ReadaheadCnt)
+	}
+	ppre.Order = pbbi.Order
+
+	pending := make(chan struct{})
+	gbi.pending = pending
+	go func() {
+		gbi.fillBuffer(ppre)
+		close(pending)
+	}()
+}
+
+// nextBuffer makes the prefetched data current and starts the next prefetch.
+// If nothing was prefetched the buffer is filled synchronously.
+func (gbi *GoBufferIterator) nextBuffer() {
+	gbi.waitPrefetch()
+	if gbi.prefetchReady {
+		gbi.bbi, gbi.prefetched = gbi.prefetched, gbi.bbi
+		gbi.prefetchReady = false
+	} else {
+		gbi.fillReadahead()
+	}
+
+	pbbi := &gbi.bbi
+	if pbbi.Err() == nil && pbbi.Cnt > 0 {
+		gbi.startPrefetch()
+	}
+}
+
 // fillReadahead tries to get new data from the underlying iterator in the current direction.
 func (gbi *GoBufferIterator) fillReadahead() {
-	pbbi := &gbi.bbi
+	gbi.fillBuffer(&gbi.bbi)
+}
+
+// fillBuffer tries to get new data from the underlying iterator in the current direction into pbbi.
+func (gbi *GoBufferIterator) fillBuffer(pbbi *goiterator.BaseBufferIterator) {
 	pbbi.Reset()
 
 	var cErr *C.char
@@ -114,7 +199,7 @@ func (gbi *GoBufferIterator) fillReadahead() {
 	if ccnt == 0 && cneeded > 0 {
 		if pbbi.AutoAdjustBufferSize {
 			pbbi.SetReadaheadSize(uint64(cneeded))
-			gbi.fillReadahead()
+			gbi.fillBuffer(pbbi)
 		} else {
 			pbbi.SetErr(ErrReadaheadBufferTooSmall)
 			return
@@ -152,18 +237,21 @@ func (gbi *GoBufferIterator) Prev() {
 
 // SeekToFirst moves the iterator to the first key in the database.
 func (gbi *GoBufferIterator) SeekToFirst() {
+	gbi.discardPrefetch()
 	gbi.bbi.Reset()
 	gbi.itr.SeekToFirst()
 }
 
 // SeekToLast moves the iterator to the last key in the database.
 func (gbi *GoBufferIterator) SeekToLast() {
+	gbi.discardPrefetch()
 	gbi.bbi.Reset()
 	gbi.itr.SeekToLast()
 }
 
 // Seek moves the iterator to the position greater than or equal to the key.
 func (gbi *GoBufferIterator) Seek(k []byte) {
+	gbi.discardPrefetch()
 	gbi.bbi.Reset()
 	gbi.itr.Seek(k)
 }
@@ -171,6 +259,7 @@ func (gbi *GoBufferIterator) Seek(k []byte) {
 // SeekForPrev moves the iterator to the last key that less than or equal
 // to the target key, in contrast with Seek.
 func (gbi *GoBufferIterator) SeekForPrev(k []byte) {
+	gbi.discardPrefetch()
 	gbi.bbi.Reset()
 	gbi.itr.SeekForPrev(k)
 }
@@ -215,13 +304,19 @@ func (gbi *GoBufferIterator) Valid() bool {
 	}
 
 	if pbbi.Cnt == 0 || pbbi.ReadPos == pbbi.Cnt {
-		gbi.fillReadahead()
+		if gbi.asyncPrefetch {
+			gbi.nextBuffer()
+		} else {
+			gbi.fillReadahead()
+		}
 	}
 	return gbi.innerValid()
 }
 
 // Reset resets the iterator to its defaults.
+// Prefetched data is dropped as well.
 func (gbi *GoBufferIterator) Reset() {
+	gbi.discardPrefetch()
 	gbi.bbi.Reset()
 }
 
@@ -232,6 +327,9 @@ func (gbi *GoBufferIterator) Err() (err error) {
 	if err != nil {
 		return
 	}
+	// the underlying iterator must not be accessed during a prefetch,
+	// its data is kept for the next buffer.
+	gbi.waitPrefetch()
 	gbi.bbi.SetErr(gbi.itr.Err())
 	return gbi.bbi.Err()
 }
@@ -242,6 +340,17 @@ func (gbi *GoBufferIterator) SetIteratorSortOrder(order goiterator.IteratorSortO
 	pbbi := &gbi.bbi
 	if pbbi.Order != goiterator.IteratorSortOrder_Natural &&
 		pbbi.Order != order {
+		// the underlying iterator is ahead of the current buffer by the
+		// prefetched entries, so these are stepped back first.
+		gbi.waitPrefetch()
+		if gbi.prefetchReady && gbi.prefetched.Cnt > 0 {
+			C.iter_move(
+				(*C.rocksdb_iterator_t)(gbi.itr.UnsafeGetUnsafeIterator()),
+				C.int64_t(order),
+				C.size_t(gbi.prefetched.Cnt),
+			)
+		}
+		gbi.discardPrefetch()
 		pbbi.Reset()
 		pbbi.Order = order
 		gbi.fillReadahead()
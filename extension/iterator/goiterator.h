@@ -17,6 +17,9 @@ void iter_valid_next_to_buffer(
 	uint32_t* plengths, size_t max_cnt, size_t* psize, size_t* pcnt,
 	size_t* pneeded, size_t* pvalid, char** errptr);
 
+// moves iter steps times in direction. If iter is not valid, the first step
+// seeks to the first or last key, so entries read past the end can be stepped back.
+void iter_move(rocksdb_iterator_t* iter, const int64_t direction, size_t steps);
 
 
 #ifdef __cplusplus
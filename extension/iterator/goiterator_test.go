@@ -134,10 +134,128 @@ func TestGoIterator(t *testing.T) {
 	goitr.Close()
 }
 
+func TestGoIteratorAsyncPrefetch(t *testing.T) {
+	db := newTestDB(t, "TestGoIteratorAsyncPrefetch", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+
+	givenKeys, givenValues := givenKeysValues(155)
+	for i, k := range givenKeys {
+		require.NoError(t, db.Put(wo, k, givenValues[i]))
+	}
+
+	ro := NewDefaultReadOptions()
+	goitr := NewGoBufferIteratorFromIterator(db.NewIterator(ro), 256, 7, true, goiterator.IteratorSortOrder_Asc)
+	goitr.SetAsyncPrefetch(true)
+	defer goitr.Close()
+
+	i := 0
+	for goitr.SeekToFirst(); goitr.Valid(); goitr.Next() {
+		k, v := goitr.KeyValue()
+		require.Equal(t, k, givenKeys[i])
+		require.Equal(t, v, givenValues[i])
+		i++
+	}
+	require.NoError(t, goitr.Err())
+	require.Equal(t, i, len(givenKeys))
+
+	// seek while a prefetch is in flight
+	i = 3
+	for goitr.Seek(givenKeys[i]); goitr.Valid(); goitr.Next() {
+		require.Equal(t, goitr.Key(), givenKeys[i])
+		i++
+		if i == 20 {
+			break
+		}
+	}
+	i = 50
+	for goitr.Seek(givenKeys[i]); goitr.Valid(); goitr.Next() {
+		require.Equal(t, goitr.Key(), givenKeys[i])
+		i++
+	}
+	require.NoError(t, goitr.Err())
+	require.Equal(t, i, len(givenKeys))
+
+	// change the direction
+	goitr.SetIteratorSortOrder(goiterator.IteratorSortOrder_Desc)
+	i = 1
+	for goitr.SeekToLast(); goitr.Valid(); goitr.Prev() {
+		require.Equal(t, goitr.Key(), givenKeys[len(givenKeys)-i])
+		require.Equal(t, goitr.Value(), givenValues[len(givenKeys)-i])
+		i++
+	}
+	require.NoError(t, goitr.Err())
+	require.Equal(t, i, len(givenKeys)+1)
+
+	// close while a prefetch is in flight
+	goitr.SeekToLast()
+	require.True(t, goitr.Valid())
+}
+
+func TestGoIteratorAsyncPrefetchSwitchDirection(t *testing.T) {
+	db := newTestDB(t, "TestGoIteratorAsyncPrefetchSwitchDirection", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+
+	givenKeys, givenValues := givenKeysValues(155)
+	for i, k := range givenKeys {
+		require.NoError(t, db.Put(wo, k, givenValues[i]))
+	}
+
+	// collect iterates forward over cnt keys, switches the direction
+	// and returns all keys read backwards.
+	collect := func(asyncPrefetch bool, cnt int) (keys [][]byte) {
+		ro := NewDefaultReadOptions()
+		goitr := NewGoBufferIteratorFromIterator(db.NewIterator(ro), 256, 7, true, goiterator.IteratorSortOrder_Asc)
+		goitr.SetAsyncPrefetch(asyncPrefetch)
+		defer goitr.Close()
+
+		i := 0
+		for goitr.SeekToFirst(); goitr.Valid() && i < cnt; goitr.Next() {
+			require.Equal(t, givenKeys[i], goitr.Key())
+			i++
+		}
+		require.NoError(t, goitr.Err())
+
+		goitr.SetIteratorSortOrder(goiterator.IteratorSortOrder_Desc)
+		for ; goitr.Valid(); goitr.Prev() {
+			keys = append(keys, append([]byte(nil), goitr.Key()...))
+		}
+		require.NoError(t, goitr.Err())
+		return keys
+	}
+
+	// switch within a buffer, at a buffer boundary and after the prefetch
+	// has read past the last key.
+	for _, cnt := range []int{10, 14, 30, 150} {
+		expected := collect(false, cnt)
+		require.NotEmpty(t, expected)
+		require.Equal(t, expected, collect(true, cnt), "switch after %d keys", cnt)
+	}
+}
+
 func BenchmarkGoIterator_Get(b *testing.B) {
+	benchmarkGoIteratorGet(b, false)
+}
+
+func BenchmarkGoIterator_GetAsyncPrefetch(b *testing.B) {
+	benchmarkGoIteratorGet(b, true)
+}
+
+func BenchmarkMultiIterator_Get(b *testing.B) {
+	benchmarkMultiIteratorGet(b, false)
+}
+
+func BenchmarkMultiIterator_GetAsyncPrefetch(b *testing.B) {
+	benchmarkMultiIteratorGet(b, true)
+}
+
+func benchmarkGoIteratorGet(b *testing.B, asyncPrefetch bool) {
 	db, err := newBenchDB("TestBenchIteratorXX", nil)
-	defer db.Close()
 	require.NoError(b, err)
+	defer db.Close()
 
 	wo := NewDefaultWriteOptions()
 
@@ -154,9 +272,40 @@ func BenchmarkGoIterator_Get(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		goitr := NewGoBufferIteratorFromIterator(iter, 4096, 1024, false, goiterator.IteratorSortOrder_Asc)
+		goitr.SetAsyncPrefetch(asyncPrefetch)
 		for goitr.SeekToFirst(); goitr.Valid(); goitr.Next() {
 			_, _ = goitr.KeyValue()
 		}
+		goitr.Reset()
+	}
+}
+
+func benchmarkMultiIteratorGet(b *testing.B, asyncPrefetch bool) {
+	db, err := newBenchDB("TestBenchMultiIteratorXX", nil)
+	require.NoError(b, err)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+
+	givenKeys, givenValues := givenKeysValues(155555)
+	for i, k := range givenKeys {
+		require.NoError(b, db.Put(wo, k, givenValues[i]))
+	}
+
+	ro := NewDefaultReadOptions()
+	itrs := []*Iterator{
+		db.NewIterator(ro), db.NewIterator(ro), db.NewIterator(ro),
+	}
+	mitr := NewFixedPrefixMultiIteratorFromIterators(4096, 1024, false, itrs, 0)
+	mitr.SetAsyncPrefetch(asyncPrefetch)
+	defer mitr.Close()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for mitr.SeekToFirst(); mitr.Valid(); mitr.Next() {
+			_, _ = mitr.KeyValue()
+		}
 	}
 }
 
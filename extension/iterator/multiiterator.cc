@@ -5,7 +5,10 @@
 #include <stdio.h>
 #include <string.h>
 #include <vector>
+#include <algorithm>
 #include "rocksdb/c.h"
+#include "rocksdb/comparator.h"
+#include "rocksdb/slice.h"
 
 extern "C" {
 
@@ -15,10 +18,39 @@ struct multiiterator_t {
 	int current_iter_idx;
 	unsigned char valid;
 
+	// > 0 forward, < 0 backward.
+	int64_t direction;
+	// max-heap of the indexes of the valid iters, only used backward.
+	std::vector<int> heap;
+
 	iters_cmp_fn iters_cmp;
+	iters_order_fn iters_order;
 	size_t iters_cmp_fix_offset;
 
 	key_cmp_fn key_cmp;
+
+	// only set for full key comparison with multiiterator_order_comparator.
+	const rocksdb::Comparator* comparator;
+};
+
+
+// multiiterator_heap_less orders iterator indexes by their current keys
+// for the max-heap used in backward direction.
+// On equal keys the higher index is greater, so the order is the exact
+// reverse of the forward direction which prefers the lower index.
+struct multiiterator_heap_less {
+	const multiiterator_t* multi_iter;
+
+	bool operator()(int a, int b) const {
+		size_t a_len, b_len;
+		const char* a_key = rocksdb_iter_key(multi_iter->iters[a], &a_len);
+		const char* b_key = rocksdb_iter_key(multi_iter->iters[b], &b_len);
+		int cmp = multi_iter->iters_order(multi_iter, a_key, a_len, b_key, b_len);
+		if (cmp != 0) {
+			return cmp < 0;
+		}
+		return a < b;
+	}
 };
 
 
@@ -32,7 +64,14 @@ multiiterator_t* create_multiiterator_by_rocksdb_iterators(
 	}
 
 	multi_iter->current_iter = NULL;
+	multi_iter->current_iter_idx = -1;
+	multi_iter->valid = 0;
+	multi_iter->direction = 1;
+	multi_iter->iters_cmp = multiiterator_cmp_all_fixed_prefix;
+	multi_iter->iters_order = multiiterator_order_fixed_prefix;
 	multi_iter->iters_cmp_fix_offset = iters_cmp_fix_offset;
+	multi_iter->key_cmp = key_memcmp;
+	multi_iter->comparator = NULL;
 
 
 	return multi_iter;
@@ -52,7 +91,9 @@ unsigned char multiiterator_seek_to_first(multiiterator_t* multi_iter, char** er
 	}
 
 	multiiterator_reset_base(multi_iter);
+	multi_iter->direction = 1;
 	multiiterator_next_and_set_valid(multi_iter);
+	return multi_iter->valid;
 }
 
 
@@ -62,7 +103,9 @@ unsigned char multiiterator_seek_to_last(multiiterator_t* multi_iter, char** err
 	}
 
 	multiiterator_reset_base(multi_iter);
-	multiiterator_next_and_set_valid(multi_iter);
+	multi_iter->direction = -1;
+	multiiterator_rebuild_heap(multi_iter);
+	return multi_iter->valid;
 }
 
 
@@ -79,7 +122,9 @@ unsigned char multiiterator_seek(
 	}
 
 	multiiterator_reset_base(multi_iter);
+	multi_iter->direction = 1;
 	multiiterator_next_and_set_valid(multi_iter);
+	return multi_iter->valid;
 }
 
 
@@ -96,7 +141,9 @@ unsigned char multiiterator_seek_for_prev(
 	}
 
 	multiiterator_reset_base(multi_iter);
-	multiiterator_next_and_set_valid(multi_iter);
+	multi_iter->direction = -1;
+	multiiterator_rebuild_heap(multi_iter);
+	return multi_iter->valid;
 }
 
 
@@ -143,8 +190,11 @@ void multiiterator_next_and_set_valid(multiiterator_t* multi_iter) {
 		rocksdb_iter_next(current_iter);
 	}
 
-	
+	multiiterator_select_next(multi_iter);
+}
 
+
+void multiiterator_select_next(multiiterator_t* multi_iter) {
 	int idx = -1;
 	multi_iter->iters_cmp(multi_iter, NULL, multi_iter->iters_cmp_fix_offset, multi_iter->key_cmp, &idx);
 	if(idx != -1) {
@@ -153,11 +203,147 @@ void multiiterator_next_and_set_valid(multiiterator_t* multi_iter) {
 		multi_iter->valid = true;
 	} else {
 		multi_iter->current_iter = NULL;
+		multi_iter->current_iter_idx = -1;
 		multi_iter->valid = 0;
 	}
 }
 
 
+void multiiterator_set_current_from_heap(multiiterator_t* multi_iter) {
+	if (multi_iter->heap.empty()) {
+		multi_iter->current_iter = NULL;
+		multi_iter->current_iter_idx = -1;
+		multi_iter->valid = 0;
+		return;
+	}
+
+	int idx = multi_iter->heap.front();
+	multi_iter->current_iter = multi_iter->iters[idx];
+	multi_iter->current_iter_idx = idx;
+	multi_iter->valid = 1;
+}
+
+
+void multiiterator_rebuild_heap(multiiterator_t* multi_iter) {
+	std::vector<int>& heap = multi_iter->heap;
+	heap.clear();
+
+	size_t num = multi_iter->iters.size();
+	for (size_t i = 0; i < num; i++) {
+		if (rocksdb_iter_valid(multi_iter->iters[i])) {
+			heap.push_back((int)i);
+		}
+	}
+
+	multiiterator_heap_less less = {multi_iter};
+	std::make_heap(heap.begin(), heap.end(), less);
+	multiiterator_set_current_from_heap(multi_iter);
+}
+
+
+void multiiterator_prev_and_set_valid(multiiterator_t* multi_iter) {
+	std::vector<int>& heap = multi_iter->heap;
+	if (heap.empty()) {
+		multiiterator_set_current_from_heap(multi_iter);
+		return;
+	}
+
+	multiiterator_heap_less less = {multi_iter};
+	std::pop_heap(heap.begin(), heap.end(), less);
+	int idx = heap.back();
+	heap.pop_back();
+
+	rocksdb_iterator_t* iter = multi_iter->iters[idx];
+	rocksdb_iter_prev(iter);
+	if (rocksdb_iter_valid(iter)) {
+		heap.push_back(idx);
+		std::push_heap(heap.begin(), heap.end(), less);
+	}
+
+	multiiterator_set_current_from_heap(multi_iter);
+}
+
+
+void multiiterator_switch_to_backward(multiiterator_t* multi_iter) {
+	// all but the current iter are positioned at their first entry after the current key,
+	// so one step back positions them at their last entry before the current key.
+	int current_idx = multi_iter->current_iter_idx;
+	size_t num = multi_iter->iters.size();
+	for (size_t i = 0; i < num; i++) {
+		rocksdb_iterator_t* iter = multi_iter->iters[i];
+		if ((int)i == current_idx) {
+			continue;
+		}
+
+		if (rocksdb_iter_valid(iter)) {
+			rocksdb_iter_prev(iter);
+		} else {
+			rocksdb_iter_seek_to_last(iter);
+		}
+	}
+
+	if (current_idx != -1 && rocksdb_iter_valid(multi_iter->iters[current_idx])) {
+		rocksdb_iter_prev(multi_iter->iters[current_idx]);
+	}
+
+	multi_iter->direction = -1;
+	multiiterator_rebuild_heap(multi_iter);
+}
+
+
+void multiiterator_switch_to_forward(multiiterator_t* multi_iter) {
+	// all but the current iter are positioned at their last entry before the current key,
+	// so one step forward positions them at their first entry after the current key.
+	int current_idx = multi_iter->current_iter_idx;
+	size_t num = multi_iter->iters.size();
+	for (size_t i = 0; i < num; i++) {
+		rocksdb_iterator_t* iter = multi_iter->iters[i];
+		if ((int)i == current_idx) {
+			continue;
+		}
+
+		if (rocksdb_iter_valid(iter)) {
+			rocksdb_iter_next(iter);
+		} else {
+			rocksdb_iter_seek_to_first(iter);
+		}
+	}
+
+	if (current_idx != -1 && rocksdb_iter_valid(multi_iter->iters[current_idx])) {
+		rocksdb_iter_next(multi_iter->iters[current_idx]);
+	}
+
+	multi_iter->direction = 1;
+	multi_iter->heap.clear();
+	multiiterator_select_next(multi_iter);
+}
+
+
+void multiiterator_move_and_set_valid(multiiterator_t* multi_iter, const int64_t direction) {
+	if (direction < 0) {
+		if (multi_iter->direction < 0) {
+			multiiterator_prev_and_set_valid(multi_iter);
+		} else {
+			multiiterator_switch_to_backward(multi_iter);
+		}
+		return;
+	}
+
+	if (multi_iter->direction < 0) {
+		multiiterator_switch_to_forward(multi_iter);
+	} else {
+		multiiterator_next_and_set_valid(multi_iter);
+	}
+}
+
+
+void multiiterator_move(multiiterator_t* multi_iter, const int64_t direction, size_t steps) {
+	for (size_t i = 0; i < steps; i++) {
+		multiiterator_move_and_set_valid(multi_iter, direction);
+	}
+}
+
+
 void multiiterator_valid_next_to_buffer(
 	multiiterator_t* multi_iter,
 	const int64_t direction, 
@@ -189,7 +375,7 @@ void multiiterator_valid_next_to_buffer(
 		plengths[plength_pos+1] = (uint32_t)value_len;
 
 		pindexes[cnt] = (uint32_t)multi_iter->current_iter_idx;
-		multiiterator_next_and_set_valid(multi_iter);
+		multiiterator_move_and_set_valid(multi_iter, direction);
 	}
 
 	*pcnt = cnt;
@@ -322,19 +508,129 @@ void multiiterator_cmp_all_fixed_prefix(
 
 
 
+void multiiterator_cmp_all_comparator(
+	multiiterator_t* multi_iter,
+	rocksdb_iterator_t* current_iter,
+	const size_t iters_cmp_fix_offset,
+	key_cmp_fn key_cmp,
+	int *next_idx) {
+	const char* min_key = NULL;
+	size_t min_key_len = 0;
+	int min_idx = -1;
+
+	const size_t num = multi_iter->iters.size();
+	for (size_t i = 0; i < num; i++) {
+		rocksdb_iterator_t* iter = multi_iter->iters[i];
+		if (!rocksdb_iter_valid(iter)) {
+			continue;
+		}
+
+		size_t key_len;
+		const char* key = rocksdb_iter_key(iter, &key_len);
+		if (min_idx == -1 || multiiterator_order_comparator(multi_iter, key, key_len, min_key, min_key_len) < 0) {
+			min_key = key;
+			min_key_len = key_len;
+			min_idx = (int)i;
+		}
+	}
+
+	*next_idx = min_idx;
+}
+
+
+int multiiterator_order_fixed_suffix(
+	const multiiterator_t* multi_iter,
+	const char* key1, size_t key1_len,
+	const char* key2, size_t key2_len) {
+	const size_t iters_cmp_fix_offset = multi_iter->iters_cmp_fix_offset;
+	if (key1_len != key2_len) {
+		return key1_len < key2_len ? -1 : 1;
+	}
+
+	if (key1_len < iters_cmp_fix_offset) {
+		return 0;
+	}
+
+	return multi_iter->key_cmp(
+		&key1[key1_len-iters_cmp_fix_offset],
+		&key2[key2_len-iters_cmp_fix_offset], key1_len - iters_cmp_fix_offset);
+}
+
+
+int multiiterator_order_fixed_prefix(
+	const multiiterator_t* multi_iter,
+	const char* key1, size_t key1_len,
+	const char* key2, size_t key2_len) {
+	const size_t iters_cmp_fix_offset = multi_iter->iters_cmp_fix_offset;
+	if (key1_len != key2_len) {
+		return key1_len < key2_len ? -1 : 1;
+	}
+
+	if (key1_len < iters_cmp_fix_offset) {
+		return 0;
+	}
+
+	return multi_iter->key_cmp(
+		&key1[iters_cmp_fix_offset],
+		&key2[iters_cmp_fix_offset], key1_len - iters_cmp_fix_offset);
+}
+
+
+int multiiterator_order_comparator(
+	const multiiterator_t* multi_iter,
+	const char* key1, size_t key1_len,
+	const char* key2, size_t key2_len) {
+	return multi_iter->comparator->Compare(
+		rocksdb::Slice(key1, key1_len), rocksdb::Slice(key2, key2_len));
+}
+
+
+
 // cmp setter 
 
 void multiiterator_set_cmp_fn(multiiterator_t* multi_iter, iters_cmp_fn iters_cmp) {
 	multi_iter->iters_cmp = iters_cmp;
+	// the backward order has to match the forward one,
+	// it is unknown for custom iters_cmp_fn until set with multiiterator_set_order_fn.
+	if (iters_cmp == multiiterator_cmp_all_fixed_suffix) {
+		multi_iter->iters_order = multiiterator_order_fixed_suffix;
+	} else if (iters_cmp == multiiterator_cmp_all_fixed_prefix) {
+		multi_iter->iters_order = multiiterator_order_fixed_prefix;
+	} else if (iters_cmp == multiiterator_cmp_all_comparator && multi_iter->comparator != NULL) {
+		multi_iter->iters_order = multiiterator_order_comparator;
+	} else {
+		multi_iter->iters_order = NULL;
+	}
 }
 
 void multiiterator_set_cmp_fn_cmp_all_fixed_suffix(multiiterator_t* multi_iter) {
 	multi_iter->iters_cmp = multiiterator_cmp_all_fixed_suffix;
+	multi_iter->iters_order = multiiterator_order_fixed_suffix;
 }
 
 
 void multiiterator_set_cmp_fn_cmp_all_fixed_prefix(multiiterator_t* multi_iter) {
 	multi_iter->iters_cmp = multiiterator_cmp_all_fixed_prefix;
+	multi_iter->iters_order = multiiterator_order_fixed_prefix;
+}
+
+
+void multiiterator_set_order_fn(multiiterator_t* multi_iter, iters_order_fn iters_order) {
+	multi_iter->iters_order = iters_order;
+}
+
+
+unsigned char multiiterator_has_order_fn(const multiiterator_t* multi_iter) {
+	return multi_iter->iters_order != NULL;
+}
+
+
+void multiiterator_set_comparator(multiiterator_t* multi_iter, const rocksdb_comparator_t* cmp) {
+	// rocksdb_comparator_t and the comparators of extension/comparator
+	// are derived from rocksdb::Comparator only.
+	multi_iter->comparator = reinterpret_cast<const rocksdb::Comparator*>(cmp);
+	multi_iter->iters_cmp = multiiterator_cmp_all_comparator;
+	multi_iter->iters_order = multiiterator_order_comparator;
 }
 
 
@@ -12,12 +12,42 @@ import (
 )
 
 // MultiIterator is used to iterate with multiple iterators at once.
-// It currently only supports forward iteration.
+// Forward iteration merges the iterators by the smallest key, backward
+// iteration (after SeekToLast, SeekForPrev or Prev) by the largest key.
+// The direction may be switched at any position with Next() and Prev().
 // Values in RocksDB must be smaller that uint32_t max.
 type MultiIterator struct {
 	c    *C.multiiterator_t
 	bbi  goiterator.IndexedBaseBufferIterator
 	itrs []*gorocksdb.Iterator
+	// releaseCmp releases the native comparator of
+	// NewComparatorMultiIteratorFromIterators if any.
+	releaseCmp func()
+
+	// asyncPrefetch enables filling prefetched on a background goroutine
+	// while bbi is consumed.
+	asyncPrefetch bool
+	prefetched    goiterator.IndexedBaseBufferIterator
+	// pending is non nil while a prefetch is in flight and
+	// is closed once the prefetch finished.
+	pending       chan struct{}
+	prefetchReady bool
+}
+
+// MultiIteratorWithAsyncPrefetch is a config for NewMultiIteratorFromIteratorsNativeUnsafe
+// which enables asynchronous double-buffered prefetching.
+func MultiIteratorWithAsyncPrefetch(mitr *MultiIterator) {
+	mitr.SetAsyncPrefetch(true)
+}
+
+// MultiIteratorWithNativeOrderFn returns a config for NewMultiIteratorFromIteratorsNativeUnsafe
+// which sets the C iters_order_fn cOrderFn used for backward iteration.
+// It is required for backward iteration with a custom cIteratorCmpFn
+// and must order the keys like cIteratorCmpFn does.
+func MultiIteratorWithNativeOrderFn(cOrderFn unsafe.Pointer) func(mitr *MultiIterator) {
+	return func(mitr *MultiIterator) {
+		C.multiiterator_set_order_fn(mitr.c, (C.iters_order_fn)(cOrderFn))
+	}
 }
 
 // NewFixedSuffixMultiIteratorFromIterators returns a newly allocated MultiIterator.
@@ -84,7 +114,58 @@ func NewFixedPrefixMultiIteratorFromIterators(
 	}
 }
 
+// NewComparatorMultiIteratorFromIterators returns a newly allocated MultiIterator
+// which merges the full keys of arbitrary length ordered by cmp.
+// cmp may be a Go Comparator or a native one, it should be the comparator
+// the iterators are ordered by.
+// The iterators itrs should not be used elsewhere from now.
+// The iterators may be of different column families or DBs.
+func NewComparatorMultiIteratorFromIterators(
+	readaheadSize, readaheadCnt uint64, autoAdjustBufferSize bool,
+	itrs []*gorocksdb.Iterator, cmp gorocksdb.Comparator,
+) (mitr *MultiIterator) {
+	cCmp, releaseCmp := gorocksdb.UnsafeGetNativeComparator(cmp)
+	mitr = NewComparatorMultiIteratorFromIteratorsUnsafe(
+		readaheadSize, readaheadCnt, autoAdjustBufferSize, itrs, cCmp)
+	mitr.releaseCmp = releaseCmp
+	return
+}
+
+// NewComparatorMultiIteratorFromIteratorsUnsafe returns a newly allocated MultiIterator
+// which merges the full keys of arbitrary length ordered by the rocksdb_comparator_t cCmp,
+// for example one of extension/comparator.
+// cCmp must not be freed before the MultiIterator is closed.
+// The iterators itrs should not be used elsewhere from now.
+func NewComparatorMultiIteratorFromIteratorsUnsafe(
+	readaheadSize, readaheadCnt uint64, autoAdjustBufferSize bool,
+	itrs []*gorocksdb.Iterator, cCmp unsafe.Pointer,
+) (mitr *MultiIterator) {
+	citrs := make([]*C.rocksdb_iterator_t, len(itrs))
+	for i, itr := range itrs {
+		citrs[i] = (*C.rocksdb_iterator_t)(itr.UnsafeGetUnsafeIterator())
+	}
+	c := C.create_multiiterator_by_rocksdb_iterators(
+		(**C.rocksdb_iterator_t)(unsafe.Pointer(&citrs[0])),
+		C.size_t(len(citrs)),
+		C.size_t(0),
+	)
+	C.multiiterator_set_comparator(c, (*C.rocksdb_comparator_t)(cCmp))
+
+	bbi := goiterator.CreateIndexedBaseBufferIterator(
+		readaheadSize, readaheadCnt, autoAdjustBufferSize)
+	bbi.Order = goiterator.IteratorSortOrder_Asc
+
+	return &MultiIterator{
+		c:    c,
+		bbi:  bbi,
+		itrs: itrs,
+	}
+}
+
 // NewMultiIteratorFromIteratorsNativeUnsafe returns a newly allocated MultiIterator.
+// A custom cIteratorCmpFn only supports forward iteration unless the matching
+// order is set with the config MultiIteratorWithNativeOrderFn, otherwise backward
+// iteration fails with ErrInvalidIteratorDirection.
 // The iterators itrs should not be used elsewhere from now.
 // The iterators must have appropriate upper bounds set.
 // The comparator of the iterators column families must be appropriate.
@@ -135,9 +216,81 @@ func NewMultiIteratorFromIteratorsNativeUnsafe(
 	return
 }
 
+// SetAsyncPrefetch enables or disables asynchronous double-buffered prefetching.
+// If enabled, the next readahead buffer is filled on a background goroutine
+// while the current one is consumed, so Valid() does not stall on the cgo call
+// each time the buffer is exhausted. This doubles the memory used for buffering.
+func (mitr *MultiIterator) SetAsyncPrefetch(enable bool) {
+	mitr.discardPrefetch()
+	if enable && mitr.prefetched.Buffer == nil {
+		pbbi := &mitr.bbi
+		mitr.prefetched = goiterator.CreateIndexedBaseBufferIterator(
+			pbbi.ReadaheadSize, pbbi.ReadaheadCnt, pbbi.AutoAdjustBufferSize)
+	}
+	mitr.asyncPrefetch = enable
+}
+
+// waitPrefetch blocks until an in flight prefetch has finished.
+func (mitr *MultiIterator) waitPrefetch() {
+	if mitr.pending == nil {
+		return
+	}
+	<-mitr.pending
+	mitr.pending = nil
+	mitr.prefetchReady = true
+}
+
+// discardPrefetch waits for an in flight prefetch and drops its data.
+// Must be called before the underlying iterators are repositioned.
+func (mitr *MultiIterator) discardPrefetch() {
+	mitr.waitPrefetch()
+	if mitr.prefetchReady {
+		mitr.prefetched.Reset()
+		mitr.prefetchReady = false
+	}
+}
+
+// startPrefetch fills prefetched from the underlying iterators on a background goroutine.
+func (mitr *MultiIterator) startPrefetch() {
+	pbbi := &mitr.bbi
+	ppre := &mitr.prefetched
+	if ppre.ReadaheadSize < pbbi.ReadaheadSize || ppre.ReadaheadCnt != pbbi.ReadaheadCnt {
+		ppre.SetReadahead(pbbi.ReadaheadSize, pbbi.ReadaheadCnt)
+	}
+	ppre.Order = pbbi.Order
+
+	pending := make(chan struct{})
+	mitr.pending = pending
+	go func() {
+		mitr.fillBuffer(ppre)
+		close(pending)
+	}()
+}
+
+// nextBuffer makes the prefetched data current and starts the next prefetch.
+// If nothing was prefetched the buffer is filled synchronously.
+func (mitr *MultiIterator) nextBuffer() {
+	mitr.waitPrefetch()
+	if mitr.prefetchReady {
+		mitr.bbi, mitr.prefetched = mitr.prefetched, mitr.bbi
+		mitr.prefetchReady = false
+	} else {
+		mitr.fillReadahead()
+	}
+
+	pbbi := &mitr.bbi
+	if pbbi.Err() == nil && pbbi.Cnt > 0 {
+		mitr.startPrefetch()
+	}
+}
+
 // fillReadahead tries to get new data from the underlying iterator in the current direction.
 func (mitr *MultiIterator) fillReadahead() {
-	pbbi := &mitr.bbi
+	mitr.fillBuffer(&mitr.bbi)
+}
+
+// fillBuffer tries to get new data from the underlying iterators in the current direction into pbbi.
+func (mitr *MultiIterator) fillBuffer(pbbi *goiterator.IndexedBaseBufferIterator) {
 	pbbi.Reset()
 
 	var cErr *C.char
@@ -164,7 +317,7 @@ func (mitr *MultiIterator) fillReadahead() {
 	if ccnt == 0 && cneeded > 0 {
 		if pbbi.AutoAdjustBufferSize {
 			pbbi.SetReadaheadSize(uint64(cneeded))
-			mitr.fillReadahead()
+			mitr.fillBuffer(pbbi)
 		} else {
 			pbbi.SetErr(ErrReadaheadBufferTooSmall)
 			return
@@ -182,7 +335,9 @@ func (mitr *MultiIterator) fillReadahead() {
 func (mitr *MultiIterator) SeekToFirst() {
 	pbbi := &mitr.bbi
 	var cErr *C.char
+	mitr.discardPrefetch()
 	pbbi.Reset()
+	pbbi.Order = goiterator.IteratorSortOrder_Asc
 	C.multiiterator_seek_to_first(mitr.c, &cErr)
 
 	if cErr != nil {
@@ -195,7 +350,12 @@ func (mitr *MultiIterator) SeekToFirst() {
 func (mitr *MultiIterator) SeekToLast() {
 	pbbi := &mitr.bbi
 	var cErr *C.char
+	mitr.discardPrefetch()
 	pbbi.Reset()
+	pbbi.Order = goiterator.IteratorSortOrder_Desc
+	if !mitr.canIterateBackward() {
+		return
+	}
 	C.multiiterator_seek_to_last(mitr.c, &cErr)
 
 	if cErr != nil {
@@ -205,8 +365,10 @@ func (mitr *MultiIterator) SeekToLast() {
 }
 
 // seekBaseKeys defines the basic seek with keys functionality.
+// order is the direction the iteration continues with.
 func (mitr *MultiIterator) seekBaseKeys(
 	keys [][]byte,
+	order goiterator.IteratorSortOrder,
 	fnCSeek func(
 		*C.multiiterator_t,
 		**C.char,
@@ -216,7 +378,12 @@ func (mitr *MultiIterator) seekBaseKeys(
 ) {
 	pbbi := &mitr.bbi
 	var cErr *C.char
+	mitr.discardPrefetch()
 	pbbi.Reset()
+	pbbi.Order = order
+	if order == goiterator.IteratorSortOrder_Desc && !mitr.canIterateBackward() {
+		return
+	}
 	keysPtrs, keysSizes := gorocksdb.ByteSlicesToUintptrsAndSizeTSlices(keys)
 	fnCSeek(
 		mitr.c,
@@ -233,7 +400,7 @@ func (mitr *MultiIterator) seekBaseKeys(
 
 // Seek moves the underlying iterators to the position greater than or equal to the keys.
 func (mitr *MultiIterator) Seek(keys [][]byte) {
-	mitr.seekBaseKeys(keys, func(
+	mitr.seekBaseKeys(keys, goiterator.IteratorSortOrder_Asc, func(
 		citr *C.multiiterator_t,
 		ckeys **C.char,
 		ckeysSizes *C.size_t,
@@ -247,8 +414,9 @@ func (mitr *MultiIterator) Seek(keys [][]byte) {
 }
 
 // SeekForPrev moves the underlying iterators to the position smaller than the keys.
+// The iteration continues backward with Prev().
 func (mitr *MultiIterator) SeekForPrev(keys [][]byte) {
-	mitr.seekBaseKeys(keys, func(
+	mitr.seekBaseKeys(keys, goiterator.IteratorSortOrder_Desc, func(
 		citr *C.multiiterator_t,
 		ckeys **C.char,
 		ckeysSizes *C.size_t,
@@ -287,19 +455,57 @@ func (mitr *MultiIterator) IteratorIndex() uint32 {
 }
 
 // Next moves the iterator to the next sequential key in the database.
+// If the iterator currently moves backward the direction is switched.
 // Must not be called if Valid is false.
 func (mitr *MultiIterator) Next() {
 	pbbi := &mitr.bbi
 	if pbbi.Order != goiterator.IteratorSortOrder_Asc {
-		pbbi.SetErr(ErrInvalidIteratorDirection)
+		mitr.switchDirection(goiterator.IteratorSortOrder_Asc)
 		return
 	}
 	pbbi.InnerNext()
 }
 
-// Prev is not implemented with this iterator.
+// Prev moves the iterator to the previous sequential key in the database.
+// If the iterator currently moves forward the direction is switched.
+// Must not be called if Valid is false.
 func (mitr *MultiIterator) Prev() {
-	mitr.bbi.SetErr(ErrInvalidIteratorDirection)
+	pbbi := &mitr.bbi
+	if pbbi.Order != goiterator.IteratorSortOrder_Desc {
+		if mitr.canIterateBackward() {
+			mitr.switchDirection(goiterator.IteratorSortOrder_Desc)
+		}
+		return
+	}
+	pbbi.InnerNext()
+}
+
+// canIterateBackward sets ErrInvalidIteratorDirection and returns false
+// if no order for backward iteration is known.
+func (mitr *MultiIterator) canIterateBackward() bool {
+	if C.multiiterator_has_order_fn(mitr.c) == 0 {
+		mitr.bbi.SetErr(ErrInvalidIteratorDirection)
+		return false
+	}
+	return true
+}
+
+// switchDirection moves the iterator one step in the direction order
+// which is the opposite of the current one.
+// The underlying iterators are ahead of the current position by the
+// buffered and prefetched entries, so these are stepped back first.
+func (mitr *MultiIterator) switchDirection(order goiterator.IteratorSortOrder) {
+	pbbi := &mitr.bbi
+	steps := pbbi.Cnt - pbbi.ReadPos + 1
+	mitr.waitPrefetch()
+	if mitr.prefetchReady {
+		steps += mitr.prefetched.Cnt
+	}
+	mitr.discardPrefetch()
+
+	C.multiiterator_move(mitr.c, C.int64_t(order), C.size_t(steps))
+	pbbi.Reset()
+	pbbi.Order = order
 }
 
 func (mitr *MultiIterator) innerValid() bool {
@@ -316,13 +522,19 @@ func (mitr *MultiIterator) Valid() bool {
 	}
 
 	if pbbi.Cnt == 0 || pbbi.ReadPos == pbbi.Cnt {
-		mitr.fillReadahead()
+		if mitr.asyncPrefetch {
+			mitr.nextBuffer()
+		} else {
+			mitr.fillReadahead()
+		}
 	}
 	return mitr.innerValid()
 }
 
 // Reset resets the iterator to its defaults.
+// Prefetched data is dropped as well.
 func (mitr *MultiIterator) Reset() {
+	mitr.discardPrefetch()
 	mitr.bbi.Reset()
 }
 
@@ -333,14 +545,28 @@ func (mitr *MultiIterator) Err() (err error) {
 	if err != nil {
 		return
 	}
-
-	return mitr.bbi.Err()
+	// the underlying iterators must not be accessed during a prefetch,
+	// its data is kept for the next buffer.
+	mitr.waitPrefetch()
+	for _, itr := range mitr.itrs {
+		if err = itr.Err(); err != nil {
+			mitr.bbi.SetErr(err)
+			return
+		}
+	}
+	return nil
 }
 
 // Close closes the iterator and ALL underlying iterators.
 // The MultiIterator is no longer usable.
+// An in flight prefetch is awaited before.
 func (mitr *MultiIterator) Close() {
+	mitr.discardPrefetch()
 	C.multiiterator_close(mitr.c)
+	if mitr.releaseCmp != nil {
+		mitr.releaseCmp()
+		mitr.releaseCmp = nil
+	}
 }
 
 // NextTo iterates with Next() up to readaheadSize or readaheadCnt and fills data into buf.
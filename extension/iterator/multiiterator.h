@@ -19,6 +19,13 @@ typedef void (*iters_cmp_fn)(
 	key_cmp_fn key_cmp,
 	int *next_idx);
 
+// iters_order_fn compares two keys of the iterators like iters_cmp_fn does
+// and returns < 0, 0, > 0. It is used for backward iteration.
+typedef int (*iters_order_fn)(
+	const multiiterator_t* multi_iter,
+	const char* key1, size_t key1_len,
+	const char* key2, size_t key2_len);
+
 
 
 multiiterator_t* create_multiiterator_by_rocksdb_iterators(
@@ -51,6 +58,18 @@ unsigned char multiiterator_key_value(
 
 void multiiterator_next_and_set_valid(multiiterator_t* multi_iter);
 
+void multiiterator_select_next(multiiterator_t* multi_iter);
+
+void multiiterator_rebuild_heap(multiiterator_t* multi_iter);
+
+void multiiterator_prev_and_set_valid(multiiterator_t* multi_iter);
+
+// moves one step in direction and switches the direction if needed.
+void multiiterator_move_and_set_valid(multiiterator_t* multi_iter, const int64_t direction);
+
+// moves steps times in direction and switches the direction if needed.
+void multiiterator_move(multiiterator_t* multi_iter, const int64_t direction, size_t steps);
+
 void multiiterator_valid_next_to_buffer(
 	multiiterator_t* multi_iter,
 	const int64_t direction, 
@@ -76,15 +95,52 @@ void multiiterator_cmp_all_fixed_prefix(
 	key_cmp_fn key_cmp,
 	int *next_idx);
 
+// compares the full keys with the comparator of the multi_iter
+// and takes the iterator with the lowest index on equal keys.
+void multiiterator_cmp_all_comparator(
+	multiiterator_t* multi_iter,
+	rocksdb_iterator_t* current_iter,
+	const size_t iters_cmp_fix_offset,
+	key_cmp_fn key_cmp,
+	int *next_idx);
+
+int multiiterator_order_fixed_suffix(
+	const multiiterator_t* multi_iter,
+	const char* key1, size_t key1_len,
+	const char* key2, size_t key2_len);
+
+int multiiterator_order_fixed_prefix(
+	const multiiterator_t* multi_iter,
+	const char* key1, size_t key1_len,
+	const char* key2, size_t key2_len);
+
+int multiiterator_order_comparator(
+	const multiiterator_t* multi_iter,
+	const char* key1, size_t key1_len,
+	const char* key2, size_t key2_len);
+
 
 // cmp setter
 
+// sets iters_cmp and the matching order for backward iteration if iters_cmp
+// is one of the multiiterator_cmp_all functions. Otherwise the order has to be
+// set with multiiterator_set_order_fn to iterate backward.
 void multiiterator_set_cmp_fn(multiiterator_t* multi_iter, iters_cmp_fn iters_cmp);
 
 void multiiterator_set_cmp_fn_cmp_all_fixed_suffix(multiiterator_t* multi_iter);
 
 void multiiterator_set_cmp_fn_cmp_all_fixed_prefix(multiiterator_t* multi_iter);
 
+void multiiterator_set_order_fn(multiiterator_t* multi_iter, iters_order_fn iters_order);
+
+// returns whether an order for backward iteration is set.
+unsigned char multiiterator_has_order_fn(const multiiterator_t* multi_iter);
+
+// sets a comparator which compares the full keys of arbitrary length,
+// cmp may be any rocksdb_comparator_t including the ones of extension/comparator.
+// cmp must outlive the multi_iter.
+void multiiterator_set_comparator(multiiterator_t* multi_iter, const rocksdb_comparator_t* cmp);
+
 
 
 // Functions for key_cmp_fn
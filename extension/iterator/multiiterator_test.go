@@ -1,12 +1,15 @@
 package iterator
 
 import (
+	"bytes"
 	"github.com/kapitan-k/goiterator"
 	. "github.com/kapitan-k/gorocksdb"
+	"github.com/kapitan-k/gorocksdb/extension/comparator"
 	"github.com/stretchr/testify/require"
 	"sort"
 	"strconv"
 	"testing"
+	"unsafe"
 )
 
 var db *DB
@@ -123,6 +126,307 @@ func TestMultiIteratorFixedSuffixAsc(t *testing.T) {
 
 }
 
+func TestMultiIteratorFixedSuffixDesc(t *testing.T) {
+	cfdb, itrs := newTestDBItrsPerTopic(t, "TestMultiIteratorFixedSuffixDesc")
+	defer cfdb.Close()
+
+	readaheadSize := uint64(1024 * 1024)
+	readaheadCnt := uint64(3)
+	suffixLen := uint64(8)
+	mitr := NewFixedSuffixMultiIteratorFromIterators(readaheadSize, readaheadCnt, true, itrs, suffixLen)
+	defer mitr.Close()
+
+	totalKeys, totalValues := sortedSuffixKeysValues(suffixLen)
+
+	j := len(totalKeys) - 1
+	for mitr.SeekToLast(); mitr.Valid(); mitr.Prev() {
+		k, v := mitr.KeyValue()
+		require.EqualValues(t, totalKeys[j], k)
+		require.EqualValues(t, totalValues[j], v)
+		j--
+	}
+	require.Equal(t, -1, j)
+	require.NoError(t, mitr.Err())
+
+	seekKeys := [][]byte{}
+	for i := range suffixKeys {
+		seekKeys = append(seekKeys, []byte("topicID"+strconv.FormatInt(int64(i+1), 10)+"eventID3"))
+	}
+
+	// the last key <= eventID3 is of topicID3
+	j = 7
+	for mitr.SeekForPrev(seekKeys); mitr.Valid(); mitr.Prev() {
+		require.EqualValues(t, totalKeys[j], mitr.Key())
+		j--
+	}
+	require.Equal(t, -1, j)
+	require.NoError(t, mitr.Err())
+}
+
+func TestMultiIteratorFixedSuffixSwitchDirection(t *testing.T) {
+	cfdb, itrs := newTestDBItrsPerTopic(t, "TestMultiIteratorFixedSuffixSwitchDirection")
+	defer cfdb.Close()
+
+	readaheadSize := uint64(1024 * 1024)
+	readaheadCnt := uint64(3)
+	suffixLen := uint64(8)
+	mitr := NewFixedSuffixMultiIteratorFromIterators(readaheadSize, readaheadCnt, true, itrs, suffixLen)
+	mitr.SetAsyncPrefetch(true)
+	defer mitr.Close()
+
+	totalKeys, totalValues := sortedSuffixKeysValues(suffixLen)
+
+	mitr.SeekToFirst()
+	for j := 0; j < 5; j++ {
+		require.True(t, mitr.Valid())
+		require.EqualValues(t, totalKeys[j], mitr.Key())
+		mitr.Next()
+	}
+
+	require.True(t, mitr.Valid())
+	require.EqualValues(t, totalKeys[5], mitr.Key())
+
+	mitr.Prev()
+	require.True(t, mitr.Valid())
+	require.EqualValues(t, totalKeys[4], mitr.Key())
+
+	mitr.Prev()
+	require.True(t, mitr.Valid())
+	require.EqualValues(t, totalKeys[3], mitr.Key())
+
+	mitr.Next()
+	require.True(t, mitr.Valid())
+	require.EqualValues(t, totalKeys[4], mitr.Key())
+
+	j := 4
+	for ; mitr.Valid(); mitr.Next() {
+		k, v := mitr.KeyValue()
+		require.EqualValues(t, totalKeys[j], k)
+		require.EqualValues(t, totalValues[j], v)
+		j++
+	}
+	require.Equal(t, len(totalKeys), j)
+
+	// switch after the end was reached
+	mitr.Prev()
+	require.True(t, mitr.Valid())
+	require.EqualValues(t, totalKeys[len(totalKeys)-1], mitr.Key())
+	require.NoError(t, mitr.Err())
+}
+
+func TestMultiIteratorAsyncPrefetch(t *testing.T) {
+	suffixLen := uint64(8)
+	totalKeys, totalValues := sortedSuffixKeysValues(suffixLen)
+
+	for _, readaheadCnt := range []uint64{1, 2, 5} {
+		cfdb, itrs := newTestDBItrsPerTopic(t, "TestMultiIteratorAsyncPrefetch"+strconv.FormatUint(readaheadCnt, 10))
+		mitr := NewFixedSuffixMultiIteratorFromIterators(1024, readaheadCnt, true, itrs, suffixLen)
+		mitr.SetAsyncPrefetch(true)
+
+		// forward
+		j := 0
+		for mitr.SeekToFirst(); mitr.Valid(); mitr.Next() {
+			k, v := mitr.KeyValue()
+			require.EqualValues(t, totalKeys[j], k)
+			require.EqualValues(t, totalValues[j], v)
+			j++
+		}
+		require.Equal(t, len(totalKeys), j)
+		require.NoError(t, mitr.Err())
+
+		// reverse
+		for mitr.SeekToLast(); mitr.Valid(); mitr.Prev() {
+			j--
+			k, v := mitr.KeyValue()
+			require.EqualValues(t, totalKeys[j], k)
+			require.EqualValues(t, totalValues[j], v)
+		}
+		require.Equal(t, 0, j)
+		require.NoError(t, mitr.Err())
+
+		// switch the direction at every position, in both directions
+		for pos := 1; pos < len(totalKeys); pos++ {
+			mitr.SeekToFirst()
+			for j = 0; j < pos; j++ {
+				require.True(t, mitr.Valid())
+				mitr.Next()
+			}
+			require.True(t, mitr.Valid())
+			require.EqualValues(t, totalKeys[pos], mitr.Key())
+			mitr.Prev()
+			require.True(t, mitr.Valid())
+			require.EqualValues(t, totalKeys[pos-1], mitr.Key(), "readaheadCnt %d pos %d", readaheadCnt, pos)
+			for j = pos - 1; mitr.Valid(); mitr.Prev() {
+				require.EqualValues(t, totalKeys[j], mitr.Key())
+				j--
+			}
+			require.Equal(t, -1, j)
+
+			mitr.SeekToLast()
+			for j = len(totalKeys) - 1; j > pos; j-- {
+				require.True(t, mitr.Valid())
+				mitr.Prev()
+			}
+			require.True(t, mitr.Valid())
+			require.EqualValues(t, totalKeys[pos], mitr.Key())
+			mitr.Next()
+			for j = pos + 1; mitr.Valid(); mitr.Next() {
+				require.EqualValues(t, totalKeys[j], mitr.Key(), "readaheadCnt %d pos %d", readaheadCnt, pos)
+				j++
+			}
+			require.Equal(t, len(totalKeys), j)
+		}
+		require.NoError(t, mitr.Err())
+
+		mitr.Close()
+		cfdb.Close()
+	}
+}
+
+type bytewiseTestComparator struct{}
+
+func (bytewiseTestComparator) Compare(a, b []byte) int { return bytes.Compare(a, b) }
+func (bytewiseTestComparator) Name() string            { return "gorocksdb.test.bytewise" }
+
+func TestMultiIteratorComparator(t *testing.T) {
+	db1 := newTestDB(t, "TestMultiIteratorComparator1", nil)
+	defer db1.Close()
+	db2 := newTestDB(t, "TestMultiIteratorComparator2", nil)
+	defer db2.Close()
+
+	wo := NewDefaultWriteOptions()
+	keys1 := [][]byte{[]byte("a"), []byte("abc"), []byte("b"), []byte("bbbbbb"), []byte("z")}
+	keys2 := [][]byte{[]byte("aa"), []byte("abcd"), []byte("c"), []byte("yyyyyyyyyyyy")}
+	for _, k := range keys1 {
+		require.NoError(t, db1.Put(wo, k, k))
+	}
+	for _, k := range keys2 {
+		require.NoError(t, db2.Put(wo, k, k))
+	}
+
+	totalKeys := append(append([][]byte{}, keys1...), keys2...)
+	sortKeys(totalKeys)
+
+	ro := NewDefaultReadOptions()
+	itrs := []*Iterator{db1.NewIterator(ro), db2.NewIterator(ro)}
+	mitr := NewComparatorMultiIteratorFromIterators(1024, 2, true, itrs, bytewiseTestComparator{})
+	defer mitr.Close()
+
+	j := 0
+	indexes := []uint32{}
+	for mitr.SeekToFirst(); mitr.Valid(); mitr.Next() {
+		k, v := mitr.KeyValue()
+		require.EqualValues(t, totalKeys[j], k)
+		require.EqualValues(t, totalKeys[j], v)
+		indexes = append(indexes, mitr.IteratorIndex())
+		j++
+	}
+	require.Equal(t, len(totalKeys), j)
+	require.Equal(t, []uint32{0, 1, 0, 1, 0, 0, 1, 1, 0}, indexes)
+	require.NoError(t, mitr.Err())
+
+	for mitr.SeekToLast(); mitr.Valid(); mitr.Prev() {
+		j--
+		require.EqualValues(t, totalKeys[j], mitr.Key())
+	}
+	require.Equal(t, 0, j)
+
+	mitr.Seek([][]byte{[]byte("abc"), []byte("abc")})
+	require.True(t, mitr.Valid())
+	require.EqualValues(t, []byte("abc"), mitr.Key())
+	require.NoError(t, mitr.Err())
+}
+
+func TestMultiIteratorNativeComparator(t *testing.T) {
+	// the comparator of extension/comparator compares the native uint64
+	// at offset 0, which differs from the bytewise order on little endian.
+	cCmp := comparator.NewSingleUint64ComparatorUnsafe(0)
+	applyOpts := func(opts *Options) {
+		opts.SetComparatorUnsafe(cCmp)
+	}
+	db1 := newTestDB(t, "TestMultiIteratorNativeComparator1", applyOpts)
+	defer db1.Close()
+	db2 := newTestDB(t, "TestMultiIteratorNativeComparator2", applyOpts)
+	defer db2.Close()
+
+	wo := NewDefaultWriteOptions()
+	ids1 := []uint64{1, 256, 65536}
+	ids2 := []uint64{2, 255, 257, 1 << 40}
+	for _, id := range ids1 {
+		require.NoError(t, db1.Put(wo, uint64Key(id), uint64Key(id)))
+	}
+	for _, id := range ids2 {
+		require.NoError(t, db2.Put(wo, uint64Key(id), uint64Key(id)))
+	}
+	totalIDs := []uint64{1, 2, 255, 256, 257, 65536, 1 << 40}
+
+	ro := NewDefaultReadOptions()
+	itrs := []*Iterator{db1.NewIterator(ro), db2.NewIterator(ro)}
+	mitr := NewComparatorMultiIteratorFromIteratorsUnsafe(1024, 2, true, itrs, cCmp)
+	defer mitr.Close()
+
+	j := 0
+	for mitr.SeekToFirst(); mitr.Valid(); mitr.Next() {
+		require.EqualValues(t, uint64Key(totalIDs[j]), mitr.Key())
+		j++
+	}
+	require.Equal(t, len(totalIDs), j)
+	require.NoError(t, mitr.Err())
+
+	for mitr.SeekToLast(); mitr.Valid(); mitr.Prev() {
+		j--
+		require.EqualValues(t, uint64Key(totalIDs[j]), mitr.Key())
+	}
+	require.Equal(t, 0, j)
+
+	mitr.Seek([][]byte{uint64Key(256), uint64Key(256)})
+	require.True(t, mitr.Valid())
+	require.EqualValues(t, uint64Key(256), mitr.Key())
+	require.NoError(t, mitr.Err())
+}
+
+// uint64Key returns id in native byte order, as read by extension/comparator.
+func uint64Key(id uint64) []byte {
+	k := make([]byte, 8)
+	*(*uint64)(unsafe.Pointer(&k[0])) = id
+	return k
+}
+
+func sortKeys(keys [][]byte) {
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+}
+
+// newTestDBItrsPerTopic creates a db with a column family per topic of suffixKeys
+// and returns an iterator per column family, so no bounds are needed.
+func newTestDBItrsPerTopic(t *testing.T, name string) (*DB, []*Iterator) {
+	cfdb := newTestDB(t, name, nil)
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+
+	itrs := make([]*Iterator, len(suffixKeys))
+	for i, keys := range suffixKeys {
+		cf, err := cfdb.CreateColumnFamily(NewDefaultOptions(), "topic"+strconv.Itoa(i))
+		require.NoError(t, err)
+		for j, key := range keys {
+			require.NoError(t, cfdb.PutCF(wo, cf, key, suffixValues[i][j]))
+		}
+		itrs[i] = cfdb.NewIteratorCF(ro, cf)
+	}
+
+	return cfdb, itrs
+}
+
+func sortedSuffixKeysValues(suffixLen uint64) (totalKeys, totalValues [][]byte) {
+	for i, keys := range suffixKeys {
+		totalKeys = append(totalKeys, keys...)
+		totalValues = append(totalValues, suffixValues[i]...)
+	}
+	sort.Sort(MultiIteratorBySuffixPlainAsc{totalKeys, totalValues, suffixLen})
+	return
+}
+
 func TestMultiIteratorResetClose1(t *testing.T) {
 	// reset and close
 	multiitr.Reset()
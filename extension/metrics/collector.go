@@ -0,0 +1,176 @@
+// Package metrics provides a prometheus.Collector for the properties and statistics of a DB.
+package metrics
+
+import (
+	"github.com/kapitan-k/gorocksdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"strings"
+)
+
+const namespace = "rocksdb"
+
+// DBProperties are the integer properties which are exported per DB.
+var DBProperties = []string{
+	gorocksdb.PropertyNumRunningCompactions,
+	gorocksdb.PropertyNumRunningFlushes,
+	gorocksdb.PropertyBackgroundErrors,
+	gorocksdb.PropertyIsWriteStopped,
+	gorocksdb.PropertyActualDelayedWriteRate,
+}
+
+// ColumnFamilyProperties are the integer properties which are exported per column family.
+var ColumnFamilyProperties = []string{
+	gorocksdb.PropertyEstimateNumKeys,
+	gorocksdb.PropertyCurSizeAllMemTables,
+	gorocksdb.PropertyNumImmutableMemTable,
+	gorocksdb.PropertyEstimatePendingCompactionBytes,
+	gorocksdb.PropertyEstimateLiveDataSize,
+	gorocksdb.PropertyTotalSstFilesSize,
+}
+
+type columnFamily struct {
+	name   string
+	handle *gorocksdb.ColumnFamilyHandle
+}
+
+// Collector is a prometheus.Collector which exports the integer properties
+// of a DB and its column families, the block cache usage and the
+// tickers and histograms of the statistics.
+type Collector struct {
+	name  string
+	db    *gorocksdb.DB
+	cfs   []columnFamily
+	cache *gorocksdb.Cache
+	stats *gorocksdb.Statistics
+
+	dbDescs       map[string]*prometheus.Desc
+	cfDescs       map[string]*prometheus.Desc
+	cacheUsage    *prometheus.Desc
+	cachePinned   *prometheus.Desc
+	tickerDesc    *prometheus.Desc
+	histogramDesc *prometheus.Desc
+}
+
+// NewCollector creates a Collector for db, the metrics are labeled with db=name.
+// Without added column families, the column family properties
+// are exported for the default column family.
+func NewCollector(name string, db *gorocksdb.DB) *Collector {
+	c := &Collector{
+		name:    name,
+		db:      db,
+		dbDescs: make(map[string]*prometheus.Desc, len(DBProperties)),
+		cfDescs: make(map[string]*prometheus.Desc, len(ColumnFamilyProperties)),
+		cacheUsage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "block_cache", "usage_bytes"),
+			"Memory usage of the block cache.",
+			[]string{"db"}, nil),
+		cachePinned: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "block_cache", "pinned_usage_bytes"),
+			"Pinned memory usage of the block cache.",
+			[]string{"db"}, nil),
+		tickerDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ticker_total"),
+			"Statistics ticker of rocksdb.",
+			[]string{"db", "ticker"}, nil),
+		histogramDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "histogram"),
+			"Statistics histogram of rocksdb.",
+			[]string{"db", "histogram"}, nil),
+	}
+
+	for _, prop := range DBProperties {
+		c.dbDescs[prop] = prometheus.NewDesc(
+			propertyMetricName(prop),
+			"Value of the DB property "+prop+".",
+			[]string{"db"}, nil)
+	}
+	for _, prop := range ColumnFamilyProperties {
+		c.cfDescs[prop] = prometheus.NewDesc(
+			propertyMetricName(prop),
+			"Value of the column family property "+prop+".",
+			[]string{"db", "cf"}, nil)
+	}
+
+	return c
+}
+
+// AddColumnFamily adds a column family whose properties are exported
+// labeled with cf=name.
+func (c *Collector) AddColumnFamily(name string, cf *gorocksdb.ColumnFamilyHandle) {
+	c.cfs = append(c.cfs, columnFamily{name: name, handle: cf})
+}
+
+// SetBlockCache sets the block cache whose usage is exported.
+func (c *Collector) SetBlockCache(cache *gorocksdb.Cache) {
+	c.cache = cache
+}
+
+// SetStatistics sets the statistics whose tickers and histograms are exported.
+func (c *Collector) SetStatistics(stats *gorocksdb.Statistics) {
+	c.stats = stats
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.dbDescs {
+		ch <- desc
+	}
+	for _, desc := range c.cfDescs {
+		ch <- desc
+	}
+	ch <- c.cacheUsage
+	ch <- c.cachePinned
+	ch <- c.tickerDesc
+	ch <- c.histogramDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, prop := range DBProperties {
+		if v, ok := c.db.GetIntProperty(prop); ok {
+			ch <- prometheus.MustNewConstMetric(c.dbDescs[prop], prometheus.GaugeValue, float64(v), c.name)
+		}
+	}
+
+	for _, prop := range ColumnFamilyProperties {
+		if len(c.cfs) == 0 {
+			if v, ok := c.db.GetIntProperty(prop); ok {
+				ch <- prometheus.MustNewConstMetric(c.cfDescs[prop], prometheus.GaugeValue, float64(v), c.name, "default")
+			}
+			continue
+		}
+		for _, cf := range c.cfs {
+			if v, ok := c.db.GetIntPropertyCF(prop, cf.handle); ok {
+				ch <- prometheus.MustNewConstMetric(c.cfDescs[prop], prometheus.GaugeValue, float64(v), c.name, cf.name)
+			}
+		}
+	}
+
+	if c.cache != nil {
+		ch <- prometheus.MustNewConstMetric(c.cacheUsage, prometheus.GaugeValue, float64(c.cache.GetUsage()), c.name)
+		ch <- prometheus.MustNewConstMetric(c.cachePinned, prometheus.GaugeValue, float64(c.cache.GetPinnedUsage()), c.name)
+	}
+
+	if c.stats != nil {
+		snapshot := c.stats.Snapshot()
+		for ticker, count := range snapshot.Tickers {
+			ch <- prometheus.MustNewConstMetric(c.tickerDesc, prometheus.CounterValue, float64(count), c.name, string(ticker))
+		}
+		for histogram, hd := range snapshot.Histograms {
+			ch <- prometheus.MustNewConstSummary(c.histogramDesc, hd.Count, float64(hd.Sum),
+				map[float64]float64{
+					0.5:  hd.Median,
+					0.95: hd.Percentile95,
+					0.99: hd.Percentile99,
+					1:    hd.Max,
+				},
+				c.name, string(histogram))
+		}
+	}
+}
+
+// propertyMetricName converts for example rocksdb.estimate-num-keys
+// to rocksdb_estimate_num_keys.
+func propertyMetricName(prop string) string {
+	return strings.NewReplacer(".", "_", "-", "_").Replace(prop)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/kapitan-k/gorocksdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestCollector")
+	require.NoError(t, err)
+
+	cache := gorocksdb.NewLRUCache(1 << 20)
+	defer cache.Destroy()
+	stats := gorocksdb.NewStatistics()
+	defer stats.Destroy()
+
+	opts := gorocksdb.NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(true)
+	opts.SetCreateIfMissingColumnFamilies(true)
+	opts.SetStatistics(stats)
+	bbto := gorocksdb.NewDefaultBlockBasedTableOptions()
+	bbto.SetBlockCache(cache)
+	opts.SetBlockBasedTableFactory(bbto)
+
+	db, cfs, err := gorocksdb.OpenDbColumnFamilies(opts, dir,
+		[]string{"default", "other"}, []*gorocksdb.Options{opts, opts})
+	require.NoError(t, err)
+	defer db.Close()
+
+	wo := gorocksdb.NewDefaultWriteOptions()
+	require.NoError(t, db.PutCF(wo, cfs[1], []byte("key"), []byte("value")))
+
+	c := NewCollector("test", db)
+	c.AddColumnFamily("default", cfs[0])
+	c.AddColumnFamily("other", cfs[1])
+	c.SetBlockCache(cache)
+	c.SetStatistics(stats)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(c))
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+
+	families := make(map[string]bool)
+	for _, mf := range mfs {
+		families[mf.GetName()] = true
+		if mf.GetName() == "rocksdb_estimate_num_keys" {
+			require.Len(t, mf.GetMetric(), 2)
+		}
+	}
+	require.True(t, families["rocksdb_estimate_num_keys"])
+	require.True(t, families["rocksdb_num_running_compactions"])
+	require.True(t, families["rocksdb_block_cache_usage_bytes"])
+	require.True(t, families["rocksdb_ticker_total"])
+	require.True(t, families["rocksdb_histogram"])
+}
@@ -0,0 +1,95 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "logger_extension.h"
+import "C"
+import "sync"
+
+// A Logger receives the lines of the rocksdb info log.
+//
+// Log is called synchronously from the threads of rocksdb,
+// so it must be safe for concurrent use and should return quickly.
+type Logger interface {
+	// Log logs a single line msg without trailing newline.
+	Log(level InfoLogLevel, msg string)
+}
+
+// LoggerFunc is a function implementing Logger.
+type LoggerFunc func(level InfoLogLevel, msg string)
+
+// Log implements Logger.
+func (f LoggerFunc) Log(level InfoLogLevel, msg string) {
+	f(level, msg)
+}
+
+// Hold references to loggers.
+var (
+	loggersMu   sync.RWMutex
+	loggers     []Logger
+	loggersFree []int
+)
+
+func registerLogger(logger Logger) int {
+	loggersMu.Lock()
+	defer loggersMu.Unlock()
+	if n := len(loggersFree); n > 0 {
+		idx := loggersFree[n-1]
+		loggersFree = loggersFree[:n-1]
+		loggers[idx] = logger
+		return idx
+	}
+	loggers = append(loggers, logger)
+	return len(loggers) - 1
+}
+
+func getLogger(idx C.uintptr_t) Logger {
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
+	return loggers[idx]
+}
+
+// SetLogger routes the info log to logger instead of the LOG file.
+// Only lines with a level >= the level set with SetInfoLogLevel are passed,
+// the header lines written on open are passed with HeaderInfoLogLevel.
+// The logger is released when the options and all DBs opened with them are gone.
+// Default: nil, rocksdb writes the LOG file in the db dir or the db log dir.
+func (opts *Options) SetLogger(logger Logger) {
+	idx := registerLogger(logger)
+	C.gorocksdb_options_set_logger(opts.c, C.uintptr_t(idx))
+}
+
+// String returns the name of the level.
+func (l InfoLogLevel) String() string {
+	switch l {
+	case DebugInfoLogLevel:
+		return "debug"
+	case InfoInfoLogLevel:
+		return "info"
+	case WarnInfoLogLevel:
+		return "warn"
+	case ErrorInfoLogLevel:
+		return "error"
+	case FatalInfoLogLevel:
+		return "fatal"
+	case HeaderInfoLogLevel:
+		return "header"
+	}
+	return "unknown"
+}
+
+//export gorocksdb_logger_log
+func gorocksdb_logger_log(idx C.uintptr_t, level C.int, cMsg *C.char, cMsgLen C.size_t) {
+	msg := charToString(cMsg, cMsgLen)
+	for len(msg) > 0 && msg[len(msg)-1] == '\n' {
+		msg = msg[:len(msg)-1]
+	}
+	getLogger(idx).Log(InfoLogLevel(level), msg)
+}
+
+//export gorocksdb_logger_destroy
+func gorocksdb_logger_destroy(idx C.uintptr_t) {
+	loggersMu.Lock()
+	defer loggersMu.Unlock()
+	loggers[idx] = nil
+	loggersFree = append(loggersFree, int(idx))
+}
@@ -0,0 +1,76 @@
+#include "logger_extension.h"
+
+#include <stdarg.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <memory>
+#include <string>
+#include "rocksdb/env.h"
+#include "rocksdb_internal.h"
+
+using rocksdb::InfoLogLevel;
+using rocksdb::Logger;
+
+// GoLogger formats the log lines and passes them to the
+// Logger registered in Go at idx.
+class GoLogger : public Logger {
+public:
+	GoLogger(uintptr_t idx, InfoLogLevel level) : Logger(level), idx_(idx) {}
+
+	~GoLogger() override {
+		gorocksdb_logger_destroy(idx_);
+	}
+
+	using Logger::Logv;
+
+	void LogHeader(const char* format, va_list ap) override {
+		Logv(InfoLogLevel::HEADER_LEVEL, format, ap);
+	}
+
+	void Logv(const char* format, va_list ap) override {
+		Logv(InfoLogLevel::INFO_LEVEL, format, ap);
+	}
+
+	void Logv(const InfoLogLevel level, const char* format, va_list ap) override {
+		if (level < GetInfoLogLevel()) {
+			return;
+		}
+
+		char buf[512];
+		va_list ap_copy;
+		va_copy(ap_copy, ap);
+		int n = vsnprintf(buf, sizeof(buf), format, ap_copy);
+		va_end(ap_copy);
+		if (n < 0) {
+			return;
+		}
+
+		if (static_cast<size_t>(n) < sizeof(buf)) {
+			gorocksdb_logger_log(idx_, static_cast<int>(level), buf, n);
+			return;
+		}
+
+		std::string msg(n + 1, '\0');
+		vsnprintf(&msg[0], msg.size(), format, ap);
+		gorocksdb_logger_log(idx_, static_cast<int>(level), &msg[0], n);
+	}
+
+private:
+	uintptr_t idx_;
+};
+
+extern "C" {
+
+
+void gorocksdb_options_set_logger(rocksdb_options_t* opts, uintptr_t idx) {
+	opts->rep.info_log = std::make_shared<GoLogger>(idx, opts->rep.info_log_level);
+}
+
+void gorocksdb_options_update_logger_level(rocksdb_options_t* opts) {
+	if (opts->rep.info_log) {
+		opts->rep.info_log->SetInfoLogLevel(opts->rep.info_log_level);
+	}
+}
+
+
+}
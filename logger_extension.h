@@ -0,0 +1,18 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+// Implemented in Go.
+extern void gorocksdb_logger_log(uintptr_t idx, int level, char* msg, size_t msg_len);
+extern void gorocksdb_logger_destroy(uintptr_t idx);
+
+void gorocksdb_options_set_logger(rocksdb_options_t* opts, uintptr_t idx);
+
+void gorocksdb_options_update_logger_level(rocksdb_options_t* opts);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -0,0 +1,44 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		levels []InfoLogLevel
+		lines  []string
+	)
+	// the logger is called from the threads of rocksdb,
+	// so the messages are checked on the test goroutine.
+	db := newTestDB(t, "TestLogger", func(opts *Options) {
+		opts.SetInfoLogLevel(DebugInfoLogLevel)
+		opts.SetLogger(LoggerFunc(func(level InfoLogLevel, msg string) {
+			mu.Lock()
+			defer mu.Unlock()
+			levels = append(levels, level)
+			lines = append(lines, msg)
+		}))
+	})
+	require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	db.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, lines)
+	foundVersion := false
+	for i, msg := range lines {
+		require.NotEqual(t, "unknown", levels[i].String())
+		require.NotContains(t, msg, "\n")
+		// the version is part of the header written on open.
+		if strings.HasPrefix(msg, "RocksDB version: ") {
+			require.Equal(t, HeaderInfoLogLevel, levels[i])
+			foundVersion = true
+		}
+	}
+	require.True(t, foundVersion)
+}
@@ -2,6 +2,8 @@ package gorocksdb
 
 // #include "rocksdb/c.h"
 // #include "gorocksdb.h"
+// #include "logger_extension.h"
+// #include "options_extension.h"
 import "C"
 import "unsafe"
 
@@ -53,6 +55,9 @@ const (
 	WarnInfoLogLevel  = InfoLogLevel(2)
 	ErrorInfoLogLevel = InfoLogLevel(3)
 	FatalInfoLogLevel = InfoLogLevel(4)
+	// HeaderInfoLogLevel is used for the header lines of a log file
+	// which are always logged.
+	HeaderInfoLogLevel = InfoLogLevel(5)
 )
 
 // Options represent all of the available options when opening a database with Open.
@@ -68,6 +73,10 @@ type Options struct {
 	cmo  *C.rocksdb_mergeoperator_t
 	cst  *C.rocksdb_slicetransform_t
 	ccf  *C.rocksdb_compactionfilter_t
+
+	// The listeners are attached to each DB opened with these options.
+	bgErrHandler   BackgroundErrorHandler
+	eventListeners []EventListener
 }
 
 // NewDefaultOptions creates the default Options.
@@ -108,6 +117,12 @@ func (opts *Options) SetComparator(value Comparator) {
 	C.rocksdb_options_set_comparator(opts.c, opts.ccmp)
 }
 
+// GetComparatorName returns the name of the comparator,
+// "leveldb.BytewiseComparator" unless one was set.
+func (opts *Options) GetComparatorName() string {
+	return C.GoString(C.gorocksdb_options_get_comparator_name(opts.c))
+}
+
 // SetComparatorUnsafe sets the comparator with an unsafe.Pointer.
 func (opts *Options) SetComparatorUnsafe(ptr unsafe.Pointer) {
 	C.rocksdb_options_set_comparator(opts.c, (*C.rocksdb_comparator_t)(ptr))
@@ -242,6 +257,7 @@ func (opts *Options) SetEnv(value *Env) {
 // Default: InfoInfoLogLevel
 func (opts *Options) SetInfoLogLevel(value InfoLogLevel) {
 	C.rocksdb_options_set_info_log_level(opts.c, C.int(value))
+	C.gorocksdb_options_update_logger_level(opts.c)
 }
 
 // IncreaseParallelism sets the parallelism.
@@ -775,6 +791,14 @@ func (opts *Options) SetAllowMmapWrites(value bool) {
 	C.rocksdb_options_set_allow_mmap_writes(opts.c, boolToChar(value))
 }
 
+// SetAllowIngestBehind enables IngestExternalFileOptions.SetIngestBehind,
+// the bottommost level is then reserved for ingested files.
+// It has to be set when the DB is created.
+// Default: false
+func (opts *Options) SetAllowIngestBehind(value bool) {
+	C.rocksdb_options_set_allow_ingest_behind(opts.c, boolToChar(value))
+}
+
 // SetUseDirectReads enable/disable direct I/O mode (O_DIRECT) for reads
 // Default: false
 func (opts *Options) SetUseDirectReads(value bool) {
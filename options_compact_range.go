@@ -0,0 +1,81 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "options_extension.h"
+import "C"
+
+// BottommostLevelCompaction specifies if a manual compaction
+// compacts the files of the bottommost level.
+type BottommostLevelCompaction uint
+
+// Bottommost level compaction policies.
+const (
+	// BottommostLevelCompactionSkip skips the bottommost level.
+	BottommostLevelCompactionSkip = BottommostLevelCompaction(0)
+	// BottommostLevelCompactionIfHaveCompactionFilter compacts the bottommost
+	// level only if a compaction filter is set.
+	BottommostLevelCompactionIfHaveCompactionFilter = BottommostLevelCompaction(1)
+	// BottommostLevelCompactionForce always compacts the bottommost level.
+	BottommostLevelCompactionForce = BottommostLevelCompaction(2)
+	// BottommostLevelCompactionForceOptimized always compacts the bottommost
+	// level, but skips the files created by this compaction.
+	BottommostLevelCompactionForceOptimized = BottommostLevelCompaction(3)
+)
+
+// CompactRangeOptions represent all of the available options for
+// a manual compaction with CompactRangeOpt.
+type CompactRangeOptions struct {
+	c *C.rocksdb_compactoptions_t
+}
+
+// NewDefaultCompactRangeOptions creates a default CompactRangeOptions object.
+func NewDefaultCompactRangeOptions() *CompactRangeOptions {
+	return NewNativeCompactRangeOptions(C.rocksdb_compactoptions_create())
+}
+
+// NewNativeCompactRangeOptions creates a CompactRangeOptions object.
+func NewNativeCompactRangeOptions(c *C.rocksdb_compactoptions_t) *CompactRangeOptions {
+	return &CompactRangeOptions{c}
+}
+
+// SetExclusiveManualCompaction specifies if automatic compactions
+// are paused while the manual compaction runs.
+// Default: true
+func (opts *CompactRangeOptions) SetExclusiveManualCompaction(value bool) {
+	C.rocksdb_compactoptions_set_exclusive_manual_compaction(opts.c, boolToChar(value))
+}
+
+// SetChangeLevel specifies if the compacted files are moved
+// to the minimum level able to hold them or to the target level.
+// Default: false
+func (opts *CompactRangeOptions) SetChangeLevel(value bool) {
+	C.rocksdb_compactoptions_set_change_level(opts.c, boolToChar(value))
+}
+
+// SetTargetLevel sets the level the compacted files are moved to
+// if SetChangeLevel is true, -1 means the minimum level able to hold them.
+// Default: -1
+func (opts *CompactRangeOptions) SetTargetLevel(value int) {
+	C.rocksdb_compactoptions_set_target_level(opts.c, C.int(value))
+}
+
+// SetBottommostLevelCompaction sets the policy for the files
+// of the bottommost level.
+// Default: BottommostLevelCompactionIfHaveCompactionFilter
+func (opts *CompactRangeOptions) SetBottommostLevelCompaction(value BottommostLevelCompaction) {
+	C.rocksdb_compactoptions_set_bottommost_level_compaction(opts.c, C.uchar(value))
+}
+
+// SetAllowWriteStall specifies if the manual compaction starts immediately
+// even if this stalls writes. Otherwise it waits until it would not
+// cause a write stall.
+// Default: false
+func (opts *CompactRangeOptions) SetAllowWriteStall(value bool) {
+	C.gorocksdb_compactoptions_set_allow_write_stall(opts.c, boolToChar(value))
+}
+
+// Destroy deallocates the CompactRangeOptions object.
+func (opts *CompactRangeOptions) Destroy() {
+	C.rocksdb_compactoptions_destroy(opts.c)
+	opts.c = nil
+}
@@ -4,6 +4,7 @@
 #include <stdio.h>
 #include <string.h>
 #include "rocksdb/c.h"
+#include "rocksdb_internal.h"
 
 extern "C" {
 
@@ -29,4 +30,25 @@ rocksdb_readoptions_t *rocksdb_readoptions_create_setup_quick(
 }
 
 
+const char* gorocksdb_options_get_comparator_name(rocksdb_options_t* opts) {
+	return opts->rep.comparator->Name();
+}
+
+void gorocksdb_ingestexternalfileoptions_set_verify_checksums_before_ingest(rocksdb_ingestexternalfileoptions_t* opts, unsigned char v) {
+	opts->rep.verify_checksums_before_ingest = v;
+}
+
+void gorocksdb_ingestexternalfileoptions_set_write_global_seqno(rocksdb_ingestexternalfileoptions_t* opts, unsigned char v) {
+	opts->rep.write_global_seqno = v;
+}
+
+void gorocksdb_ingestexternalfileoptions_set_fail_if_not_bottommost_level(rocksdb_ingestexternalfileoptions_t* opts, unsigned char v) {
+	opts->rep.fail_if_not_bottommost_level = v;
+}
+
+void gorocksdb_compactoptions_set_allow_write_stall(rocksdb_compactoptions_t* opts, unsigned char v) {
+	opts->rep.allow_write_stall = v;
+}
+
+
 }
\ No newline at end of file
@@ -13,6 +13,20 @@ rocksdb_readoptions_t *rocksdb_readoptions_create_setup_quick(
 	size_t readahead_size,
 	unsigned char pin_data);
 
+/* Options */
+
+// The name is owned by the comparator of opts.
+const char* gorocksdb_options_get_comparator_name(rocksdb_options_t* opts);
+
+/* IngestExternalFileOptions */
+
+void gorocksdb_ingestexternalfileoptions_set_verify_checksums_before_ingest(rocksdb_ingestexternalfileoptions_t* opts, unsigned char v);
+void gorocksdb_ingestexternalfileoptions_set_write_global_seqno(rocksdb_ingestexternalfileoptions_t* opts, unsigned char v);
+void gorocksdb_ingestexternalfileoptions_set_fail_if_not_bottommost_level(rocksdb_ingestexternalfileoptions_t* opts, unsigned char v);
+
+/* CompactRangeOptions */
+
+void gorocksdb_compactoptions_set_allow_write_stall(rocksdb_compactoptions_t* opts, unsigned char v);
 
 #ifdef __cplusplus
 }  /* end extern "C" */
@@ -1,6 +1,7 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "options_extension.h"
 import "C"
 
 // IngestExternalFileOptions represents available options when ingesting external files.
@@ -46,6 +47,36 @@ func (opts *IngestExternalFileOptions) SetAllowBlockingFlush(flag bool) {
 	C.rocksdb_ingestexternalfileoptions_set_allow_blocking_flush(opts.c, boolToChar(flag))
 }
 
+// SetIngestBehind sets ingest_behind. If set to true, the files are ingested
+// into the bottommost level behind all existing keys, duplicate keys are skipped.
+// The DB has to be opened with Options.SetAllowIngestBehind(true).
+// Default to false.
+func (opts *IngestExternalFileOptions) SetIngestBehind(flag bool) {
+	C.rocksdb_ingestexternalfileoptions_set_ingest_behind(opts.c, boolToChar(flag))
+}
+
+// SetVerifyChecksumsBeforeIngest sets verify_checksums_before_ingest. If set to true,
+// the checksums of all blocks of the files are verified before they are ingested.
+// Default to false.
+func (opts *IngestExternalFileOptions) SetVerifyChecksumsBeforeIngest(flag bool) {
+	C.gorocksdb_ingestexternalfileoptions_set_verify_checksums_before_ingest(opts.c, boolToChar(flag))
+}
+
+// SetWriteGlobalSeqno sets write_global_seqno. If set to true, the global sequence number
+// is written into the ingested files, which is required for rocksdb versions before 5.16
+// to read them. If false, the files are not modified.
+// Default to false, true before rocksdb 7.0.
+func (opts *IngestExternalFileOptions) SetWriteGlobalSeqno(flag bool) {
+	C.gorocksdb_ingestexternalfileoptions_set_write_global_seqno(opts.c, boolToChar(flag))
+}
+
+// SetFailIfNotBottommostLevel sets fail_if_not_bottommost_level. If set to true,
+// the ingestion fails if the files cannot be ingested into the bottommost level.
+// Default to false.
+func (opts *IngestExternalFileOptions) SetFailIfNotBottommostLevel(flag bool) {
+	C.gorocksdb_ingestexternalfileoptions_set_fail_if_not_bottommost_level(opts.c, boolToChar(flag))
+}
+
 // Destroy deallocates the IngestExternalFileOptions object.
 func (opts *IngestExternalFileOptions) Destroy() {
 	C.rocksdb_ingestexternalfileoptions_destroy(opts.c)
@@ -0,0 +1,213 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "perf_context_extension.h"
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// PerfLevel controls which counters and timers of the
+// PerfContext and IOStatsContext are collected.
+type PerfLevel int
+
+// Perf levels.
+const (
+	// PerfLevelDisable disables the perf stats.
+	PerfLevelDisable = PerfLevel(1)
+	// PerfLevelEnableCount enables only the count stats.
+	PerfLevelEnableCount = PerfLevel(2)
+	// PerfLevelEnableTimeExceptForMutex enables the count stats and the time stats
+	// except for the mutexes.
+	PerfLevelEnableTimeExceptForMutex = PerfLevel(3)
+	// PerfLevelEnableTimeAndCPUTimeExceptForMutex additionally enables the cpu time stats.
+	PerfLevelEnableTimeAndCPUTimeExceptForMutex = PerfLevel(4)
+	// PerfLevelEnableTime enables all stats.
+	PerfLevelEnableTime = PerfLevel(5)
+)
+
+// PerfContext contains the counters and timers (in nanoseconds)
+// of the operations of a thread, see rocksdb/perf_context.h.
+type PerfContext struct {
+	UserKeyComparisonCount     uint64
+	BlockCacheHitCount         uint64
+	BlockReadCount             uint64
+	BlockReadByte              uint64
+	BlockReadTime              uint64
+	BlockChecksumTime          uint64
+	BlockDecompressTime        uint64
+	GetReadBytes               uint64
+	MultigetReadBytes          uint64
+	IterReadBytes              uint64
+	InternalKeySkippedCount    uint64
+	InternalDeleteSkippedCount uint64
+	InternalRecentSkippedCount uint64
+	InternalMergeCount         uint64
+	GetSnapshotTime            uint64
+	GetFromMemtableTime        uint64
+	GetFromMemtableCount       uint64
+	GetPostProcessTime         uint64
+	GetFromOutputFilesTime     uint64
+	SeekOnMemtableTime         uint64
+	SeekOnMemtableCount        uint64
+	NextOnMemtableCount        uint64
+	PrevOnMemtableCount        uint64
+	SeekChildSeekTime          uint64
+	SeekChildSeekCount         uint64
+	SeekMinHeapTime            uint64
+	SeekMaxHeapTime            uint64
+	SeekInternalSeekTime       uint64
+	FindNextUserEntryTime      uint64
+	WriteWALTime               uint64
+	WriteMemtableTime          uint64
+	WriteDelayTime             uint64
+	WritePreAndPostProcessTime uint64
+	DBMutexLockNanos           uint64
+	DBConditionWaitNanos       uint64
+	BloomMemtableHitCount      uint64
+	BloomMemtableMissCount     uint64
+	BloomSstHitCount           uint64
+	BloomSstMissCount          uint64
+}
+
+// IOStatsContext contains the IO counters and timers (in nanoseconds)
+// of the operations of a thread, see rocksdb/iostats_context.h.
+type IOStatsContext struct {
+	BytesWritten      uint64
+	BytesRead         uint64
+	OpenNanos         uint64
+	AllocateNanos     uint64
+	WriteNanos        uint64
+	ReadNanos         uint64
+	RangeSyncNanos    uint64
+	FsyncNanos        uint64
+	PrepareWriteNanos uint64
+	LoggerNanos       uint64
+}
+
+// The perf level and the contexts are thread local in rocksdb,
+// but goroutines can migrate between OS threads.
+// So the functions below are only meaningful if the calling goroutine is locked
+// to its thread with runtime.LockOSThread from setting the perf level
+// until reading the contexts. MeasurePerf does that.
+
+// SetPerfLevel sets the perf level of the current thread.
+func SetPerfLevel(level PerfLevel) {
+	C.gorocksdb_set_perf_level(C.int(level))
+}
+
+// GetPerfLevel returns the perf level of the current thread.
+func GetPerfLevel() PerfLevel {
+	return PerfLevel(C.gorocksdb_get_perf_level())
+}
+
+// ResetPerfContext resets the PerfContext of the current thread.
+func ResetPerfContext() {
+	C.gorocksdb_perfcontext_reset()
+}
+
+// GetPerfContext returns the PerfContext of the current thread.
+func GetPerfContext() *PerfContext {
+	var c C.gorocksdb_perfcontext_t
+	C.gorocksdb_perfcontext_get(&c)
+	return &PerfContext{
+		UserKeyComparisonCount:     uint64(c.user_key_comparison_count),
+		BlockCacheHitCount:         uint64(c.block_cache_hit_count),
+		BlockReadCount:             uint64(c.block_read_count),
+		BlockReadByte:              uint64(c.block_read_byte),
+		BlockReadTime:              uint64(c.block_read_time),
+		BlockChecksumTime:          uint64(c.block_checksum_time),
+		BlockDecompressTime:        uint64(c.block_decompress_time),
+		GetReadBytes:               uint64(c.get_read_bytes),
+		MultigetReadBytes:          uint64(c.multiget_read_bytes),
+		IterReadBytes:              uint64(c.iter_read_bytes),
+		InternalKeySkippedCount:    uint64(c.internal_key_skipped_count),
+		InternalDeleteSkippedCount: uint64(c.internal_delete_skipped_count),
+		InternalRecentSkippedCount: uint64(c.internal_recent_skipped_count),
+		InternalMergeCount:         uint64(c.internal_merge_count),
+		GetSnapshotTime:            uint64(c.get_snapshot_time),
+		GetFromMemtableTime:        uint64(c.get_from_memtable_time),
+		GetFromMemtableCount:       uint64(c.get_from_memtable_count),
+		GetPostProcessTime:         uint64(c.get_post_process_time),
+		GetFromOutputFilesTime:     uint64(c.get_from_output_files_time),
+		SeekOnMemtableTime:         uint64(c.seek_on_memtable_time),
+		SeekOnMemtableCount:        uint64(c.seek_on_memtable_count),
+		NextOnMemtableCount:        uint64(c.next_on_memtable_count),
+		PrevOnMemtableCount:        uint64(c.prev_on_memtable_count),
+		SeekChildSeekTime:          uint64(c.seek_child_seek_time),
+		SeekChildSeekCount:         uint64(c.seek_child_seek_count),
+		SeekMinHeapTime:            uint64(c.seek_min_heap_time),
+		SeekMaxHeapTime:            uint64(c.seek_max_heap_time),
+		SeekInternalSeekTime:       uint64(c.seek_internal_seek_time),
+		FindNextUserEntryTime:      uint64(c.find_next_user_entry_time),
+		WriteWALTime:               uint64(c.write_wal_time),
+		WriteMemtableTime:          uint64(c.write_memtable_time),
+		WriteDelayTime:             uint64(c.write_delay_time),
+		WritePreAndPostProcessTime: uint64(c.write_pre_and_post_process_time),
+		DBMutexLockNanos:           uint64(c.db_mutex_lock_nanos),
+		DBConditionWaitNanos:       uint64(c.db_condition_wait_nanos),
+		BloomMemtableHitCount:      uint64(c.bloom_memtable_hit_count),
+		BloomMemtableMissCount:     uint64(c.bloom_memtable_miss_count),
+		BloomSstHitCount:           uint64(c.bloom_sst_hit_count),
+		BloomSstMissCount:          uint64(c.bloom_sst_miss_count),
+	}
+}
+
+// PerfContextReport returns the non-zero counters of the PerfContext
+// of the current thread as text.
+func PerfContextReport() string {
+	cValue := C.gorocksdb_perfcontext_report()
+	defer C.free(unsafe.Pointer(cValue))
+	return C.GoString(cValue)
+}
+
+// ResetIOStatsContext resets the IOStatsContext of the current thread.
+func ResetIOStatsContext() {
+	C.gorocksdb_iostatscontext_reset()
+}
+
+// GetIOStatsContext returns the IOStatsContext of the current thread.
+func GetIOStatsContext() *IOStatsContext {
+	var c C.gorocksdb_iostatscontext_t
+	C.gorocksdb_iostatscontext_get(&c)
+	return &IOStatsContext{
+		BytesWritten:      uint64(c.bytes_written),
+		BytesRead:         uint64(c.bytes_read),
+		OpenNanos:         uint64(c.open_nanos),
+		AllocateNanos:     uint64(c.allocate_nanos),
+		WriteNanos:        uint64(c.write_nanos),
+		ReadNanos:         uint64(c.read_nanos),
+		RangeSyncNanos:    uint64(c.range_sync_nanos),
+		FsyncNanos:        uint64(c.fsync_nanos),
+		PrepareWriteNanos: uint64(c.prepare_write_nanos),
+		LoggerNanos:       uint64(c.logger_nanos),
+	}
+}
+
+// IOStatsContextReport returns the non-zero counters of the IOStatsContext
+// of the current thread as text.
+func IOStatsContextReport() string {
+	cValue := C.gorocksdb_iostatscontext_report()
+	defer C.free(unsafe.Pointer(cValue))
+	return C.GoString(cValue)
+}
+
+// MeasurePerf runs fn with the perf level set and returns the PerfContext
+// and IOStatsContext of the operations of fn.
+// The calling goroutine is locked to its OS thread while fn runs,
+// so fn must not start the measured operations on other goroutines.
+func MeasurePerf(level PerfLevel, fn func()) (*PerfContext, *IOStatsContext) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	prevLevel := GetPerfLevel()
+	SetPerfLevel(level)
+	defer SetPerfLevel(prevLevel)
+
+	ResetPerfContext()
+	ResetIOStatsContext()
+	fn()
+	return GetPerfContext(), GetIOStatsContext()
+}
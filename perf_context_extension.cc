@@ -0,0 +1,98 @@
+#include "perf_context_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include "rocksdb/iostats_context.h"
+#include "rocksdb/perf_context.h"
+#include "rocksdb/perf_level.h"
+
+using rocksdb::IOStatsContext;
+using rocksdb::PerfContext;
+using rocksdb::PerfLevel;
+
+extern "C" {
+
+
+void gorocksdb_set_perf_level(int level) {
+	rocksdb::SetPerfLevel(static_cast<PerfLevel>(level));
+}
+
+int gorocksdb_get_perf_level() {
+	return static_cast<int>(rocksdb::GetPerfLevel());
+}
+
+void gorocksdb_perfcontext_reset() {
+	rocksdb::get_perf_context()->Reset();
+}
+
+void gorocksdb_perfcontext_get(gorocksdb_perfcontext_t* ctx) {
+	const PerfContext* pc = rocksdb::get_perf_context();
+	ctx->user_key_comparison_count = pc->user_key_comparison_count;
+	ctx->block_cache_hit_count = pc->block_cache_hit_count;
+	ctx->block_read_count = pc->block_read_count;
+	ctx->block_read_byte = pc->block_read_byte;
+	ctx->block_read_time = pc->block_read_time;
+	ctx->block_checksum_time = pc->block_checksum_time;
+	ctx->block_decompress_time = pc->block_decompress_time;
+	ctx->get_read_bytes = pc->get_read_bytes;
+	ctx->multiget_read_bytes = pc->multiget_read_bytes;
+	ctx->iter_read_bytes = pc->iter_read_bytes;
+	ctx->internal_key_skipped_count = pc->internal_key_skipped_count;
+	ctx->internal_delete_skipped_count = pc->internal_delete_skipped_count;
+	ctx->internal_recent_skipped_count = pc->internal_recent_skipped_count;
+	ctx->internal_merge_count = pc->internal_merge_count;
+	ctx->get_snapshot_time = pc->get_snapshot_time;
+	ctx->get_from_memtable_time = pc->get_from_memtable_time;
+	ctx->get_from_memtable_count = pc->get_from_memtable_count;
+	ctx->get_post_process_time = pc->get_post_process_time;
+	ctx->get_from_output_files_time = pc->get_from_output_files_time;
+	ctx->seek_on_memtable_time = pc->seek_on_memtable_time;
+	ctx->seek_on_memtable_count = pc->seek_on_memtable_count;
+	ctx->next_on_memtable_count = pc->next_on_memtable_count;
+	ctx->prev_on_memtable_count = pc->prev_on_memtable_count;
+	ctx->seek_child_seek_time = pc->seek_child_seek_time;
+	ctx->seek_child_seek_count = pc->seek_child_seek_count;
+	ctx->seek_min_heap_time = pc->seek_min_heap_time;
+	ctx->seek_max_heap_time = pc->seek_max_heap_time;
+	ctx->seek_internal_seek_time = pc->seek_internal_seek_time;
+	ctx->find_next_user_entry_time = pc->find_next_user_entry_time;
+	ctx->write_wal_time = pc->write_wal_time;
+	ctx->write_memtable_time = pc->write_memtable_time;
+	ctx->write_delay_time = pc->write_delay_time;
+	ctx->write_pre_and_post_process_time = pc->write_pre_and_post_process_time;
+	ctx->db_mutex_lock_nanos = pc->db_mutex_lock_nanos;
+	ctx->db_condition_wait_nanos = pc->db_condition_wait_nanos;
+	ctx->bloom_memtable_hit_count = pc->bloom_memtable_hit_count;
+	ctx->bloom_memtable_miss_count = pc->bloom_memtable_miss_count;
+	ctx->bloom_sst_hit_count = pc->bloom_sst_hit_count;
+	ctx->bloom_sst_miss_count = pc->bloom_sst_miss_count;
+}
+
+char* gorocksdb_perfcontext_report() {
+	return strdup(rocksdb::get_perf_context()->ToString(true).c_str());
+}
+
+void gorocksdb_iostatscontext_reset() {
+	rocksdb::get_iostats_context()->Reset();
+}
+
+void gorocksdb_iostatscontext_get(gorocksdb_iostatscontext_t* ctx) {
+	const IOStatsContext* ioc = rocksdb::get_iostats_context();
+	ctx->bytes_written = ioc->bytes_written;
+	ctx->bytes_read = ioc->bytes_read;
+	ctx->open_nanos = ioc->open_nanos;
+	ctx->allocate_nanos = ioc->allocate_nanos;
+	ctx->write_nanos = ioc->write_nanos;
+	ctx->read_nanos = ioc->read_nanos;
+	ctx->range_sync_nanos = ioc->range_sync_nanos;
+	ctx->fsync_nanos = ioc->fsync_nanos;
+	ctx->prepare_write_nanos = ioc->prepare_write_nanos;
+	ctx->logger_nanos = ioc->logger_nanos;
+}
+
+char* gorocksdb_iostatscontext_report() {
+	return strdup(rocksdb::get_iostats_context()->ToString(true).c_str());
+}
+
+
+}
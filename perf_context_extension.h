@@ -0,0 +1,85 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+typedef struct {
+	uint64_t user_key_comparison_count;
+	uint64_t block_cache_hit_count;
+	uint64_t block_read_count;
+	uint64_t block_read_byte;
+	uint64_t block_read_time;
+	uint64_t block_checksum_time;
+	uint64_t block_decompress_time;
+	uint64_t get_read_bytes;
+	uint64_t multiget_read_bytes;
+	uint64_t iter_read_bytes;
+	uint64_t internal_key_skipped_count;
+	uint64_t internal_delete_skipped_count;
+	uint64_t internal_recent_skipped_count;
+	uint64_t internal_merge_count;
+	uint64_t get_snapshot_time;
+	uint64_t get_from_memtable_time;
+	uint64_t get_from_memtable_count;
+	uint64_t get_post_process_time;
+	uint64_t get_from_output_files_time;
+	uint64_t seek_on_memtable_time;
+	uint64_t seek_on_memtable_count;
+	uint64_t next_on_memtable_count;
+	uint64_t prev_on_memtable_count;
+	uint64_t seek_child_seek_time;
+	uint64_t seek_child_seek_count;
+	uint64_t seek_min_heap_time;
+	uint64_t seek_max_heap_time;
+	uint64_t seek_internal_seek_time;
+	uint64_t find_next_user_entry_time;
+	uint64_t write_wal_time;
+	uint64_t write_memtable_time;
+	uint64_t write_delay_time;
+	uint64_t write_pre_and_post_process_time;
+	uint64_t db_mutex_lock_nanos;
+	uint64_t db_condition_wait_nanos;
+	uint64_t bloom_memtable_hit_count;
+	uint64_t bloom_memtable_miss_count;
+	uint64_t bloom_sst_hit_count;
+	uint64_t bloom_sst_miss_count;
+} gorocksdb_perfcontext_t;
+
+typedef struct {
+	uint64_t bytes_written;
+	uint64_t bytes_read;
+	uint64_t open_nanos;
+	uint64_t allocate_nanos;
+	uint64_t write_nanos;
+	uint64_t read_nanos;
+	uint64_t range_sync_nanos;
+	uint64_t fsync_nanos;
+	uint64_t prepare_write_nanos;
+	uint64_t logger_nanos;
+} gorocksdb_iostatscontext_t;
+
+// The perf level and the contexts are thread local.
+
+void gorocksdb_set_perf_level(int level);
+
+int gorocksdb_get_perf_level();
+
+void gorocksdb_perfcontext_reset();
+
+void gorocksdb_perfcontext_get(gorocksdb_perfcontext_t* ctx);
+
+// Returns a report of the non-zero counters, it has to be freed.
+char* gorocksdb_perfcontext_report();
+
+void gorocksdb_iostatscontext_reset();
+
+void gorocksdb_iostatscontext_get(gorocksdb_iostatscontext_t* ctx);
+
+// Returns a report of the non-zero counters, it has to be freed.
+char* gorocksdb_iostatscontext_report();
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
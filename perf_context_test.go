@@ -0,0 +1,51 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func TestMeasurePerf(t *testing.T) {
+	db := newTestDB(t, "TestMeasurePerf", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, db.Put(wo, []byte("key"+strconv.Itoa(i)), []byte("value")))
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, db.Delete(wo, []byte("key"+strconv.Itoa(i))))
+	}
+	require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+
+	pc, ioc := MeasurePerf(PerfLevelEnableTime, func() {
+		v, err := db.GetBytes(ro, []byte("key7"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), v)
+	})
+	require.True(t, pc.UserKeyComparisonCount > 0)
+	require.True(t, pc.GetFromOutputFilesTime > 0)
+	require.True(t, pc.GetReadBytes > 0)
+	require.NotNil(t, ioc)
+
+	pc, _ = MeasurePerf(PerfLevelEnableCount, func() {
+		itr := db.NewIterator(ro)
+		defer itr.Close()
+		for itr.SeekToFirst(); itr.Valid(); itr.Next() {
+		}
+	})
+	require.Equal(t, uint64(5), pc.InternalDeleteSkippedCount)
+	require.True(t, pc.IterReadBytes > 0)
+}
+
+func TestPerfLevel(t *testing.T) {
+	MeasurePerf(PerfLevelEnableCount, func() {
+		require.Equal(t, PerfLevelEnableCount, GetPerfLevel())
+		ResetPerfContext()
+		require.Equal(t, uint64(0), GetPerfContext().BlockReadCount)
+		_ = PerfContextReport()
+		_ = IOStatsContextReport()
+	})
+}
@@ -0,0 +1,159 @@
+package gorocksdb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrWalGap is returned by RestoreDBToPointInTime if the write ahead log
+// of the source DB misses writes, for example because the log files were
+// deleted or writes were made with disabled WAL.
+var ErrWalGap = errors.New("gap in write ahead log")
+
+// RestoreTarget describes up to which write the write ahead log is
+// replayed by RestoreDBToPointInTime. If both are set, the earlier
+// of both is the target.
+type RestoreTarget struct {
+	// SequenceNumber is the sequence number of the last replayed write,
+	// 0 means no limit.
+	SequenceNumber uint64
+	// Time is the time up to which the writes are replayed,
+	// the zero time means no limit.
+	// As the WAL does not contain timestamps, this is resolved with the
+	// modification times of the log files: only log files which were
+	// last written before Time are replayed.
+	Time time.Time
+}
+
+// RestoreDBToPointInTime restores the backup with id to dbDir and replays
+// the write ahead log of source up to target.
+// The write ahead log of source has to reach back to the backup, so the
+// obsolete log files have to be archived with SetWALTtlSeconds or
+// SetWalSizeLimitMb.
+// Only whole write batches are replayed, the restore stops before the
+// first batch crossing target.
+// The restored DB is opened with opts for all its column families.
+// It returns the sequence number of the last applied write of source.
+func (b *BackupEngine) RestoreDBToPointInTime(
+	source *DB,
+	id uint32,
+	dbDir string,
+	opts *Options,
+	target RestoreTarget,
+) (uint64, error) {
+	targetSeq, err := resolveRestoreTarget(source, target)
+	if err != nil {
+		return 0, err
+	}
+
+	ro := NewRestoreOptions()
+	defer ro.Destroy()
+	if err := b.RestoreDBFromBackup(id, dbDir, dbDir, ro); err != nil {
+		return 0, err
+	}
+
+	cfNames, err := ListColumnFamilies(opts, dbDir)
+	if err != nil {
+		return 0, err
+	}
+	cfOpts := make([]*Options, len(cfNames))
+	for i := range cfOpts {
+		cfOpts[i] = opts
+	}
+	db, cfs, err := OpenDbColumnFamilies(opts, dbDir, cfNames, cfOpts)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		for _, cf := range cfs {
+			cf.Destroy()
+		}
+		db.Close()
+	}()
+
+	lastSeq := db.GetLatestSequenceNumber()
+	if lastSeq > targetSeq {
+		return 0, fmt.Errorf("restore target %d is before backup %d at %d", targetSeq, id, lastSeq)
+	}
+	if lastSeq == targetSeq {
+		return lastSeq, nil
+	}
+
+	lastSeq, err = replayWal(source, db, lastSeq, targetSeq)
+	if err != nil {
+		return 0, err
+	}
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	return lastSeq, db.Flush(fo)
+}
+
+// resolveRestoreTarget returns the sequence number of the last write to replay.
+func resolveRestoreTarget(source *DB, target RestoreTarget) (uint64, error) {
+	targetSeq := source.GetLatestSequenceNumber()
+	if target.SequenceNumber != 0 && target.SequenceNumber < targetSeq {
+		targetSeq = target.SequenceNumber
+	}
+	if target.Time.IsZero() {
+		return targetSeq, nil
+	}
+
+	files, err := source.GetSortedWalFiles()
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		if file.ModificationTime.After(target.Time) {
+			// the writes of this and all later files are after target.Time.
+			if file.StartSequence == 0 {
+				targetSeq = 0
+			} else if file.StartSequence <= targetSeq {
+				targetSeq = file.StartSequence - 1
+			}
+			break
+		}
+	}
+	return targetSeq, nil
+}
+
+// replayWal applies the write batches of source after lastSeq up to targetSeq to db.
+// It returns the sequence number of the last applied write.
+func replayWal(source, db *DB, lastSeq, targetSeq uint64) (uint64, error) {
+	iter, err := source.GetUpdatesSince(lastSeq + 1)
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Destroy()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	for ; iter.Valid(); iter.Next() {
+		batch, seq := iter.GetBatch()
+		count := uint64(batch.Count())
+		if count == 0 || seq+count-1 <= lastSeq {
+			// the batch is contained in the backup.
+			batch.Destroy()
+			continue
+		}
+		if seq+count-1 > targetSeq {
+			batch.Destroy()
+			break
+		}
+		if seq != lastSeq+1 {
+			batch.Destroy()
+			return 0, ErrWalGap
+		}
+
+		err := db.Write(wo, batch)
+		batch.Destroy()
+		if err != nil {
+			return 0, err
+		}
+		lastSeq = seq + count - 1
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	return lastSeq, nil
+}
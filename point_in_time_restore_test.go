@@ -0,0 +1,120 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRestoreDBToPointInTime(t *testing.T) {
+	backupDir, err := ioutil.TempDir("", "gorocksdb-TestRestoreDBToPointInTime-backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(backupDir)
+
+	db := newTestDB(t, "TestRestoreDBToPointInTime", func(opts *Options) {
+		opts.SetWALTtlSeconds(3600)
+	})
+	defer db.Close()
+
+	opts := NewDefaultOptions()
+	be, err := OpenBackupEngine(opts, backupDir)
+	require.NoError(t, err)
+	defer be.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	require.NoError(t, be.CreateNewBackupFlush(db, true))
+	backupID := be.GetBackupInfo()[0].ID
+
+	require.NoError(t, db.Put(wo, []byte("key2"), []byte("value2")))
+	targetSeq := db.GetLatestSequenceNumber()
+	require.NoError(t, db.Put(wo, []byte("key3"), []byte("value3")))
+
+	restore := func(target RestoreTarget) (*DB, uint64) {
+		dir, err := ioutil.TempDir("", "gorocksdb-TestRestoreDBToPointInTime-restore")
+		require.NoError(t, err)
+		lastSeq, err := be.RestoreDBToPointInTime(db, backupID, dir, opts, target)
+		require.NoError(t, err)
+		restored, err := OpenDb(opts, dir)
+		require.NoError(t, err)
+		return restored, lastSeq
+	}
+
+	ro := NewDefaultReadOptions()
+	restored, lastSeq := restore(RestoreTarget{SequenceNumber: targetSeq})
+	defer restored.Close()
+	require.Equal(t, targetSeq, lastSeq)
+	for key, value := range map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2"), "key3": nil} {
+		v, err := restored.GetBytes(ro, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, v)
+	}
+
+	restoredLatest, lastSeq := restore(RestoreTarget{})
+	defer restoredLatest.Close()
+	require.Equal(t, db.GetLatestSequenceNumber(), lastSeq)
+	v, err := restoredLatest.GetBytes(ro, []byte("key3"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value3"), v)
+}
+
+func TestRestoreDBToPointInTimeByTime(t *testing.T) {
+	backupDir, err := ioutil.TempDir("", "gorocksdb-TestRestoreDBToPointInTimeByTime-backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(backupDir)
+
+	db := newTestDB(t, "TestRestoreDBToPointInTimeByTime", func(opts *Options) {
+		opts.SetWALTtlSeconds(3600)
+	})
+	defer db.Close()
+
+	opts := NewDefaultOptions()
+	be, err := OpenBackupEngine(opts, backupDir)
+	require.NoError(t, err)
+	defer be.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	require.NoError(t, be.CreateNewBackupFlush(db, true))
+	backupID := be.GetBackupInfo()[0].ID
+
+	// key2 is the last write of its log file, the flush starts the next one.
+	require.NoError(t, db.Put(wo, []byte("key2"), []byte("value2")))
+	key2Seq := db.GetLatestSequenceNumber()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	require.NoError(t, db.Flush(fo))
+	files, err := db.GetSortedWalFiles()
+	require.NoError(t, err)
+	var key2File *WalFile
+	for i := range files {
+		if files[i].StartSequence == key2Seq {
+			key2File = &files[i]
+		}
+	}
+	require.NotNil(t, key2File)
+	require.False(t, key2File.ModificationTime.IsZero())
+
+	// the modification times of the log files have a resolution of seconds.
+	time.Sleep(1100 * time.Millisecond)
+	require.NoError(t, db.Put(wo, []byte("key3"), []byte("value3")))
+
+	dir, err := ioutil.TempDir("", "gorocksdb-TestRestoreDBToPointInTimeByTime-restore")
+	require.NoError(t, err)
+	target := RestoreTarget{Time: key2File.ModificationTime.Add(500 * time.Millisecond)}
+	lastSeq, err := be.RestoreDBToPointInTime(db, backupID, dir, opts, target)
+	require.NoError(t, err)
+	require.Equal(t, key2Seq, lastSeq)
+
+	restored, err := OpenDb(opts, dir)
+	require.NoError(t, err)
+	defer restored.Close()
+	ro := NewDefaultReadOptions()
+	for key, value := range map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2"), "key3": nil} {
+		v, err := restored.GetBytes(ro, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, v)
+	}
+}
@@ -0,0 +1,67 @@
+#ifndef GOROCKSDB_ROCKSDB_INTERNAL_H
+#define GOROCKSDB_ROCKSDB_INTERNAL_H
+
+// Definitions of the structs behind the opaque handles of the rocksdb C API.
+// They must match the definitions in rocksdb/db/c.cc
+// and may only be used from C++ extensions.
+
+#include <stdlib.h>
+#include <string.h>
+#include "rocksdb/c.h"
+#include "rocksdb/db.h"
+#include "rocksdb/env.h"
+#include "rocksdb/options.h"
+#include "rocksdb/sst_file_reader.h"
+#include "rocksdb/sst_file_writer.h"
+#include "rocksdb/utilities/backup_engine.h"
+#include "rocksdb/utilities/checkpoint.h"
+#include "rocksdb/utilities/transaction_db.h"
+#include "rocksdb/version.h"
+
+// The extensions use rocksdb/utilities/backup_engine.h,
+// IngestExternalFileOptions::fail_if_not_bottommost_level and
+// the relative_filename and directory of the file metadata,
+// which are all part of RocksDB 6.29. RocksDB 7 removed deprecated
+// options of the C API that options.go still sets.
+static_assert(ROCKSDB_MAJOR == 6 && ROCKSDB_MINOR >= 29,
+	"gorocksdb requires RocksDB 6.29");
+
+struct rocksdb_t { rocksdb::DB* rep; };
+struct rocksdb_options_t { rocksdb::Options rep; };
+struct rocksdb_column_family_handle_t { rocksdb::ColumnFamilyHandle* rep; };
+struct rocksdb_backup_engine_t { rocksdb::BackupEngine* rep; };
+struct rocksdb_backup_engine_options_t { rocksdb::BackupEngineOptions rep; };
+struct rocksdb_restore_options_t { rocksdb::RestoreOptions rep; };
+struct rocksdb_transactiondb_t { rocksdb::TransactionDB* rep; };
+struct rocksdb_ingestexternalfileoptions_t { rocksdb::IngestExternalFileOptions rep; };
+struct rocksdb_checkpoint_t { rocksdb::Checkpoint* rep; };
+struct rocksdb_compactoptions_t {
+	rocksdb::CompactRangeOptions rep;
+	// stack variable to set a pointer to in CompactRangeOptions
+	rocksdb::Slice full_history_ts_low;
+};
+struct rocksdb_envoptions_t { rocksdb::EnvOptions rep; };
+struct rocksdb_sstfilewriter_t { rocksdb::SstFileWriter* rep; };
+struct rocksdb_iterator_t { rocksdb::Iterator* rep; };
+struct rocksdb_readoptions_t {
+	rocksdb::ReadOptions rep;
+	// stack variables to set pointers to in ReadOptions
+	rocksdb::Slice upper_bound;
+	rocksdb::Slice lower_bound;
+	rocksdb::Slice timestamp;
+	rocksdb::Slice iter_start_ts;
+};
+
+// SaveError stores the message of a not ok status in errptr like the rocksdb C API.
+static inline bool SaveError(char** errptr, const rocksdb::Status& s) {
+	if (s.ok()) {
+		return false;
+	}
+	if (*errptr != NULL) {
+		free(*errptr);
+	}
+	*errptr = strdup(s.ToString().c_str());
+	return true;
+}
+
+#endif  // GOROCKSDB_ROCKSDB_INTERNAL_H
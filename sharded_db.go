@@ -0,0 +1,501 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// ErrUnsupportedWriteBatchRecord is returned if a WriteBatch that should be split
+// into shards contains records other than Put, Merge and Delete of the default column family.
+var ErrUnsupportedWriteBatchRecord = errors.New("Unsupported write batch record for sharding")
+
+// ShardPartitioner decides which shard of a ShardedDB stores a key.
+type ShardPartitioner interface {
+	// Shard returns the index of the shard for key in [0, numShards).
+	Shard(key []byte, numShards int) int
+}
+
+// HashPartitioner distributes keys by the FNV-1a hash of the key.
+type HashPartitioner struct{}
+
+// Shard returns the index of the shard for key.
+func (HashPartitioner) Shard(key []byte, numShards int) int {
+	h := fnv.New64a()
+	h.Write(key)
+	return int(h.Sum64() % uint64(numShards))
+}
+
+// RangePartitioner distributes keys by byte-wise ordered ranges.
+// Shard i stores the keys < SplitKeys[i] which are not stored in a shard before,
+// the last shard stores all the keys >= the last split key.
+// So there have to be numShards-1 SplitKeys.
+type RangePartitioner struct {
+	SplitKeys [][]byte
+}
+
+// NewRangePartitioner creates a RangePartitioner with the ascending splitKeys.
+func NewRangePartitioner(splitKeys [][]byte) *RangePartitioner {
+	return &RangePartitioner{SplitKeys: splitKeys}
+}
+
+// Shard returns the index of the shard for key.
+func (p *RangePartitioner) Shard(key []byte, numShards int) int {
+	idx := sort.Search(len(p.SplitKeys), func(i int) bool {
+		return bytes.Compare(key, p.SplitKeys[i]) < 0
+	})
+	if idx >= numShards {
+		return numShards - 1
+	}
+	return idx
+}
+
+// ShardedDB routes the operations to one of multiple DB instances (shards),
+// for example to spread the I/O across disks.
+// Writes which affect more than one shard are NOT atomic across the shards.
+type ShardedDB struct {
+	shards      []*DB
+	partitioner ShardPartitioner
+	cmp         Comparator
+}
+
+// NewShardedDB creates a ShardedDB from the opened shards.
+// The order of the shards must be the same each time for the same data.
+func NewShardedDB(shards []*DB, partitioner ShardPartitioner) *ShardedDB {
+	return &ShardedDB{
+		shards:      shards,
+		partitioner: partitioner,
+	}
+}
+
+// OpenShardedDb opens a database per name with the specified options
+// and creates a ShardedDB of them.
+func OpenShardedDb(opts *Options, names []string, partitioner ShardPartitioner) (*ShardedDB, error) {
+	if len(names) == 0 {
+		return nil, errors.New("must provide at least one shard name")
+	}
+
+	shards := make([]*DB, 0, len(names))
+	for _, name := range names {
+		db, err := OpenDb(opts, name)
+		if err != nil {
+			for _, shard := range shards {
+				shard.Close()
+			}
+			return nil, err
+		}
+		shards = append(shards, db)
+	}
+
+	return NewShardedDB(shards, partitioner), nil
+}
+
+// SetComparator sets the comparator which is used to merge the shards
+// in the ShardedIterator. It must be the same the shards use.
+// Default: byte-wise ordering
+func (sdb *ShardedDB) SetComparator(cmp Comparator) {
+	sdb.cmp = cmp
+}
+
+// Shards returns the underlying shards.
+func (sdb *ShardedDB) Shards() []*DB {
+	return sdb.shards
+}
+
+// ShardIndex returns the index of the shard which stores key.
+func (sdb *ShardedDB) ShardIndex(key []byte) int {
+	return sdb.partitioner.Shard(key, len(sdb.shards))
+}
+
+// Shard returns the shard which stores key.
+func (sdb *ShardedDB) Shard(key []byte) *DB {
+	return sdb.shards[sdb.ShardIndex(key)]
+}
+
+// Get returns the data associated with the key from the shard of the key.
+func (sdb *ShardedDB) Get(opts *ReadOptions, key []byte) ([]byte, error) {
+	return sdb.Shard(key).Get(opts, key)
+}
+
+// GetBytes is like Get but returns a copy of the data.
+func (sdb *ShardedDB) GetBytes(opts *ReadOptions, key []byte) ([]byte, error) {
+	return sdb.Shard(key).GetBytes(opts, key)
+}
+
+// Put writes data associated with a key to the shard of the key.
+func (sdb *ShardedDB) Put(opts *WriteOptions, key, value []byte) error {
+	return sdb.Shard(key).Put(opts, key, value)
+}
+
+// Delete removes the data associated with the key from the shard of the key.
+func (sdb *ShardedDB) Delete(opts *WriteOptions, key []byte) error {
+	return sdb.Shard(key).Delete(opts, key)
+}
+
+// Merge merges the data associated with the key with the actual data in the shard of the key.
+func (sdb *ShardedDB) Merge(opts *WriteOptions, key []byte, value []byte) error {
+	return sdb.Shard(key).Merge(opts, key, value)
+}
+
+// SplitWriteBatch splits batch into a WriteBatch per shard.
+// Shards without records get a nil WriteBatch.
+// Only Put, Merge and Delete records of the default column family are supported.
+// The returned batches must be destroyed by the caller.
+func (sdb *ShardedDB) SplitWriteBatch(batch *WriteBatch) ([]*WriteBatch, error) {
+	batches := make([]*WriteBatch, len(sdb.shards))
+	destroy := func() {
+		for _, b := range batches {
+			if b != nil {
+				b.Destroy()
+			}
+		}
+	}
+
+	iter := batch.NewIterator()
+	for iter.Next() {
+		rec := iter.Record()
+		idx := sdb.ShardIndex(rec.Key)
+		b := batches[idx]
+		if b == nil {
+			b = NewWriteBatch()
+			batches[idx] = b
+		}
+
+		switch rec.Type {
+		case WriteBatchRecordTypeValue:
+			b.Put(rec.Key, rec.Value)
+		case WriteBatchRecordTypeMerge:
+			b.Merge(rec.Key, rec.Value)
+		case WriteBatchRecordTypeDeletion:
+			b.Delete(rec.Key)
+		default:
+			destroy()
+			return nil, ErrUnsupportedWriteBatchRecord
+		}
+	}
+	if err := iter.Error(); err != nil {
+		destroy()
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+// Write splits batch per shard and writes the parts to the shards.
+// The write is atomic per shard only, on error some shards may
+// already have been written.
+func (sdb *ShardedDB) Write(opts *WriteOptions, batch *WriteBatch) error {
+	batches, err := sdb.SplitWriteBatch(batch)
+	if err != nil {
+		return err
+	}
+
+	for i, b := range batches {
+		if b == nil {
+			continue
+		}
+		if err == nil {
+			err = sdb.shards[i].Write(opts, b)
+		}
+		b.Destroy()
+	}
+	return err
+}
+
+// NewIterator returns a ShardedIterator over all the shards
+// that uses the ReadOptions given.
+func (sdb *ShardedDB) NewIterator(opts *ReadOptions) *ShardedIterator {
+	itrs := make([]*Iterator, len(sdb.shards))
+	for i, shard := range sdb.shards {
+		itrs[i] = shard.NewIterator(opts)
+	}
+	return NewShardedIterator(itrs, sdb.cmp)
+}
+
+// each runs fn for all shards in parallel and returns the first error.
+func (sdb *ShardedDB) each(fn func(db *DB) error) error {
+	errs := make([]error, len(sdb.shards))
+	var wg sync.WaitGroup
+	for i, shard := range sdb.shards {
+		wg.Add(1)
+		go func(i int, shard *DB) {
+			defer wg.Done()
+			errs[i] = fn(shard)
+		}(i, shard)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush triggers a manual flush for all shards in parallel.
+func (sdb *ShardedDB) Flush(opts *FlushOptions) error {
+	return sdb.each(func(db *DB) error {
+		return db.Flush(opts)
+	})
+}
+
+// CompactRange runs a manual compaction on the Range of keys given
+// on all shards in parallel.
+func (sdb *ShardedDB) CompactRange(r Range) {
+	sdb.each(func(db *DB) error {
+		db.CompactRange(r)
+		return nil
+	})
+}
+
+// GetProperty returns the value of a database property for each shard.
+func (sdb *ShardedDB) GetProperty(propName string) []string {
+	values := make([]string, len(sdb.shards))
+	for i, shard := range sdb.shards {
+		values[i] = shard.GetProperty(propName)
+	}
+	return values
+}
+
+// GetAggregatedIntProperty returns the sum of an integer property of the
+// default column family of all shards, for example PropertyEstimateNumKeys.
+// It returns false if propName is not an integer property.
+func (sdb *ShardedDB) GetAggregatedIntProperty(propName string) (uint64, bool) {
+	var sum uint64
+	for _, shard := range sdb.shards {
+		v, ok := shard.GetIntProperty(propName)
+		if !ok {
+			return 0, false
+		}
+		sum += v
+	}
+	return sum, true
+}
+
+// Close closes all shards.
+func (sdb *ShardedDB) Close() {
+	for _, shard := range sdb.shards {
+		shard.Close()
+	}
+}
+
+// ShardedIterator merges the iterators of the shards of a ShardedDB
+// to a globally ordered iterator.
+// The direction may be switched at any position with Next() and Prev().
+type ShardedIterator struct {
+	itrs    []*Iterator
+	cmp     func(a, b []byte) int
+	h       shardedIteratorHeap
+	current int
+	forward bool
+}
+
+// NewShardedIterator creates a ShardedIterator which merges itrs ordered by cmp.
+// If cmp is nil byte-wise ordering is used.
+// The iterators itrs should not be used elsewhere from now.
+func NewShardedIterator(itrs []*Iterator, cmp Comparator) *ShardedIterator {
+	sitr := &ShardedIterator{
+		itrs:    itrs,
+		cmp:     bytes.Compare,
+		current: -1,
+		forward: true,
+	}
+	if cmp != nil {
+		sitr.cmp = cmp.Compare
+	}
+	sitr.h.sitr = sitr
+	return sitr
+}
+
+// shardedIteratorHeap is a min-heap forward and a max-heap backward
+// of the indexes of the valid iterators.
+type shardedIteratorHeap struct {
+	sitr *ShardedIterator
+	idxs []int
+}
+
+func (h *shardedIteratorHeap) Len() int { return len(h.idxs) }
+func (h *shardedIteratorHeap) Less(i, j int) bool {
+	a, b := h.idxs[i], h.idxs[j]
+	c := h.sitr.cmp(h.sitr.itrs[a].Key(), h.sitr.itrs[b].Key())
+	if c == 0 {
+		// prefer the lower shard forward and the higher shard backward.
+		if h.sitr.forward {
+			return a < b
+		}
+		return a > b
+	}
+	if h.sitr.forward {
+		return c < 0
+	}
+	return c > 0
+}
+func (h *shardedIteratorHeap) Swap(i, j int) { h.idxs[i], h.idxs[j] = h.idxs[j], h.idxs[i] }
+func (h *shardedIteratorHeap) Push(x interface{}) {
+	h.idxs = append(h.idxs, x.(int))
+}
+func (h *shardedIteratorHeap) Pop() interface{} {
+	n := len(h.idxs)
+	x := h.idxs[n-1]
+	h.idxs = h.idxs[:n-1]
+	return x
+}
+
+// rebuild builds the heap from the valid iterators and sets the current one.
+func (sitr *ShardedIterator) rebuild(forward bool) {
+	sitr.forward = forward
+	sitr.h.idxs = sitr.h.idxs[:0]
+	for i, itr := range sitr.itrs {
+		if itr.Valid() {
+			sitr.h.idxs = append(sitr.h.idxs, i)
+		}
+	}
+	heap.Init(&sitr.h)
+	sitr.setCurrent()
+}
+
+func (sitr *ShardedIterator) setCurrent() {
+	if len(sitr.h.idxs) == 0 {
+		sitr.current = -1
+		return
+	}
+	sitr.current = sitr.h.idxs[0]
+}
+
+// Valid returns false only when all shards have iterated past either the
+// first or the last key.
+func (sitr *ShardedIterator) Valid() bool {
+	return sitr.current != -1
+}
+
+// SeekToFirst moves the iterator to the first key of all shards.
+func (sitr *ShardedIterator) SeekToFirst() {
+	for _, itr := range sitr.itrs {
+		itr.SeekToFirst()
+	}
+	sitr.rebuild(true)
+}
+
+// SeekToLast moves the iterator to the last key of all shards.
+func (sitr *ShardedIterator) SeekToLast() {
+	for _, itr := range sitr.itrs {
+		itr.SeekToLast()
+	}
+	sitr.rebuild(false)
+}
+
+// Seek moves the iterator to the position greater than or equal to the key.
+func (sitr *ShardedIterator) Seek(key []byte) {
+	for _, itr := range sitr.itrs {
+		itr.Seek(key)
+	}
+	sitr.rebuild(true)
+}
+
+// SeekForPrev moves the iterator to the last key that less than or equal
+// to the target key, in contrast with Seek.
+func (sitr *ShardedIterator) SeekForPrev(key []byte) {
+	for _, itr := range sitr.itrs {
+		itr.SeekForPrev(key)
+	}
+	sitr.rebuild(false)
+}
+
+// Next moves the iterator to the next sequential key.
+// Must not be called if Valid is false.
+func (sitr *ShardedIterator) Next() {
+	if !sitr.forward {
+		// position all other shards after the current key.
+		key := append([]byte{}, sitr.Key()...)
+		for i, itr := range sitr.itrs {
+			if i == sitr.current {
+				continue
+			}
+			itr.Seek(key)
+			if itr.Valid() && sitr.cmp(itr.Key(), key) == 0 {
+				itr.Next()
+			}
+		}
+		sitr.itrs[sitr.current].Next()
+		sitr.rebuild(true)
+		return
+	}
+
+	sitr.advanceCurrent((*Iterator).Next)
+}
+
+// Prev moves the iterator to the previous sequential key.
+// Must not be called if Valid is false.
+func (sitr *ShardedIterator) Prev() {
+	if sitr.forward {
+		// position all other shards before the current key.
+		key := append([]byte{}, sitr.Key()...)
+		for i, itr := range sitr.itrs {
+			if i == sitr.current {
+				continue
+			}
+			itr.SeekForPrev(key)
+			if itr.Valid() && sitr.cmp(itr.Key(), key) == 0 {
+				itr.Prev()
+			}
+		}
+		sitr.itrs[sitr.current].Prev()
+		sitr.rebuild(false)
+		return
+	}
+
+	sitr.advanceCurrent((*Iterator).Prev)
+}
+
+func (sitr *ShardedIterator) advanceCurrent(move func(*Iterator)) {
+	itr := sitr.itrs[sitr.current]
+	move(itr)
+	if itr.Valid() {
+		heap.Fix(&sitr.h, 0)
+	} else {
+		heap.Pop(&sitr.h)
+	}
+	sitr.setCurrent()
+}
+
+// Key returns the key the iterator currently holds.
+func (sitr *ShardedIterator) Key() []byte {
+	return sitr.itrs[sitr.current].Key()
+}
+
+// Value returns the value the iterator currently holds.
+func (sitr *ShardedIterator) Value() []byte {
+	return sitr.itrs[sitr.current].Value()
+}
+
+// KeyValue returns the key and the value the iterator currently holds.
+func (sitr *ShardedIterator) KeyValue() ([]byte, []byte) {
+	return sitr.itrs[sitr.current].KeyValue()
+}
+
+// ShardIndex returns the index of the shard which provides the current key.
+func (sitr *ShardedIterator) ShardIndex() int {
+	return sitr.current
+}
+
+// Err returns the first error of the iterators of the shards.
+func (sitr *ShardedIterator) Err() error {
+	for _, itr := range sitr.itrs {
+		if err := itr.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the iterators of all shards.
+func (sitr *ShardedIterator) Close() {
+	for _, itr := range sitr.itrs {
+		itr.Close()
+	}
+	sitr.current = -1
+}
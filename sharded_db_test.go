@@ -0,0 +1,148 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func newTestShardedDB(t *testing.T, name string, partitioner ShardPartitioner) *ShardedDB {
+	shards := make([]*DB, 3)
+	for i := range shards {
+		shards[i] = newTestDB(t, name+strconv.Itoa(i), nil)
+	}
+	return NewShardedDB(shards, partitioner)
+}
+
+func TestRangePartitioner(t *testing.T) {
+	p := NewRangePartitioner([][]byte{[]byte("h"), []byte("p")})
+	require.Equal(t, 0, p.Shard([]byte("a"), 3))
+	require.Equal(t, 1, p.Shard([]byte("h"), 3))
+	require.Equal(t, 1, p.Shard([]byte("o"), 3))
+	require.Equal(t, 2, p.Shard([]byte("p"), 3))
+	require.Equal(t, 2, p.Shard([]byte("z"), 3))
+}
+
+func TestShardedDBCRUD(t *testing.T) {
+	sdb := newTestShardedDB(t, "TestShardedDBCRUD", HashPartitioner{})
+	defer sdb.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+
+	for i := 0; i < 100; i++ {
+		key := []byte("key" + strconv.Itoa(i))
+		require.NoError(t, sdb.Put(wo, key, key))
+	}
+
+	for i := 0; i < 100; i++ {
+		key := []byte("key" + strconv.Itoa(i))
+		v, err := sdb.GetBytes(ro, key)
+		require.NoError(t, err)
+		require.Equal(t, key, v)
+
+		v, err = sdb.Shard(key).GetBytes(ro, key)
+		require.NoError(t, err)
+		require.Equal(t, key, v)
+	}
+
+	require.NoError(t, sdb.Delete(wo, []byte("key0")))
+	v, err := sdb.GetBytes(ro, []byte("key0"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	require.NoError(t, sdb.Flush(NewDefaultFlushOptions()))
+	sdb.CompactRange(Range{nil, nil})
+
+	cnt, ok := sdb.GetAggregatedIntProperty(PropertyEstimateNumKeys)
+	require.True(t, ok)
+	require.True(t, cnt > 0)
+	_, ok = sdb.GetAggregatedIntProperty(PropertyStats)
+	require.False(t, ok)
+}
+
+func TestShardedDBWriteBatch(t *testing.T) {
+	sdb := newTestShardedDB(t, "TestShardedDBWriteBatch", NewRangePartitioner([][]byte{[]byte("h"), []byte("p")}))
+	defer sdb.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	require.NoError(t, sdb.Put(wo, []byte("x"), []byte("old")))
+
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.Put([]byte("a"), []byte("1"))
+	wb.Put([]byte("k"), []byte("2"))
+	wb.Put([]byte("q"), []byte("3"))
+	wb.Delete([]byte("x"))
+
+	batches, err := sdb.SplitWriteBatch(wb)
+	require.NoError(t, err)
+	require.Len(t, batches, 3)
+	require.Equal(t, 1, batches[0].Count())
+	require.Equal(t, 1, batches[1].Count())
+	require.Equal(t, 2, batches[2].Count())
+	for _, b := range batches {
+		b.Destroy()
+	}
+
+	require.NoError(t, sdb.Write(wo, wb))
+
+	for i, key := range []string{"a", "k", "q"} {
+		v, err := sdb.shards[i].GetBytes(ro, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, []byte(strconv.Itoa(i+1)), v)
+	}
+	v, err := sdb.GetBytes(ro, []byte("x"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
+
+func TestShardedIterator(t *testing.T) {
+	sdb := newTestShardedDB(t, "TestShardedIterator", HashPartitioner{})
+	defer sdb.Close()
+
+	wo := NewDefaultWriteOptions()
+	keys := make([]string, 0, 50)
+	for i := 10; i < 60; i++ {
+		key := "key" + strconv.Itoa(i)
+		keys = append(keys, key)
+		require.NoError(t, sdb.Put(wo, []byte(key), []byte(key)))
+	}
+
+	itr := sdb.NewIterator(NewDefaultReadOptions())
+	defer itr.Close()
+
+	i := 0
+	for itr.SeekToFirst(); itr.Valid(); itr.Next() {
+		k, v := itr.KeyValue()
+		require.Equal(t, keys[i], string(k))
+		require.Equal(t, keys[i], string(v))
+		require.Equal(t, sdb.ShardIndex(k), itr.ShardIndex())
+		i++
+	}
+	require.NoError(t, itr.Err())
+	require.Equal(t, len(keys), i)
+
+	i = len(keys) - 1
+	for itr.SeekToLast(); itr.Valid(); itr.Prev() {
+		require.Equal(t, keys[i], string(itr.Key()))
+		i--
+	}
+	require.Equal(t, -1, i)
+
+	// switch the direction in the middle.
+	itr.Seek([]byte("key30"))
+	require.Equal(t, "key30", string(itr.Key()))
+	itr.Next()
+	require.Equal(t, "key31", string(itr.Key()))
+	itr.Prev()
+	require.Equal(t, "key30", string(itr.Key()))
+	itr.Prev()
+	require.Equal(t, "key29", string(itr.Key()))
+	itr.Next()
+	require.Equal(t, "key30", string(itr.Key()))
+
+	itr.SeekForPrev([]byte("key305"))
+	require.Equal(t, "key30", string(itr.Key()))
+}
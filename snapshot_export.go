@@ -0,0 +1,207 @@
+package gorocksdb
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SnapshotChecksumsFile is the name of the last entry of an exported snapshot
+// which contains the sha256 checksums of all other entries,
+// in the format of sha256sum.
+const SnapshotChecksumsFile = "SHA256SUMS"
+
+// ErrSnapshotChecksumMismatch is returned by ImportSnapshot if a file
+// does not match its checksum.
+var ErrSnapshotChecksumMismatch = errors.New("snapshot checksum mismatch")
+
+// ExportSnapshot creates a checkpoint of the database and streams it
+// as tar archive to w. The checkpoint is created in a temporary
+// directory next to the database, so the table files are hard linked
+// instead of copied, and it is removed afterwards.
+// Use ImportSnapshot to restore the database from the archive.
+func (db *DB) ExportSnapshot(w io.Writer) error {
+	tmpDir, err := ioutil.TempDir(filepath.Dir(db.Name()), "gorocksdb-snapshot")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	checkpoint, err := db.NewCheckpoint()
+	if err != nil {
+		return err
+	}
+	defer checkpoint.Destroy()
+
+	// the checkpoint dir must not exist.
+	checkpointDir := filepath.Join(tmpDir, "checkpoint")
+	if err := checkpoint.CreateCheckpoint(checkpointDir, 0); err != nil {
+		return err
+	}
+
+	return writeSnapshotTar(w, checkpointDir)
+}
+
+func writeSnapshotTar(w io.Writer, dir string) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	var sums bytes.Buffer
+	for _, info := range infos {
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("unexpected checkpoint entry %s", info.Name())
+		}
+		sum, err := writeSnapshotFile(tw, filepath.Join(dir, info.Name()), info)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&sums, "%s  %s\n", sum, info.Name())
+	}
+
+	hdr := &tar.Header{
+		Name: SnapshotChecksumsFile,
+		Mode: 0644,
+		Size: int64(sums.Len()),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := tw.Write(sums.Bytes()); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeSnapshotFile(tw *tar.Writer, path string, info os.FileInfo) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return "", err
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, h), f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ImportSnapshot extracts a snapshot exported with DB.ExportSnapshot
+// from r to dir and verifies the checksums of all files.
+// dir must not exist, it is removed again if the import fails.
+// Afterwards the database can be opened at dir.
+func ImportSnapshot(r io.Reader, dir string) (err error) {
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("snapshot import dir %s already exists", dir)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	sums := make(map[string]string)
+	var expectedSums []byte
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if expectedSums != nil {
+			return fmt.Errorf("unexpected snapshot entry %s after %s", hdr.Name, SnapshotChecksumsFile)
+		}
+
+		if hdr.Name == SnapshotChecksumsFile {
+			if expectedSums, err = ioutil.ReadAll(tr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if hdr.Typeflag != tar.TypeReg || hdr.Name != filepath.Base(hdr.Name) ||
+			strings.HasPrefix(hdr.Name, ".") {
+			return fmt.Errorf("invalid snapshot entry %s", hdr.Name)
+		}
+		sum, err := extractSnapshotFile(tr, filepath.Join(dir, hdr.Name), os.FileMode(hdr.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		sums[hdr.Name] = sum
+	}
+
+	if expectedSums == nil {
+		return fmt.Errorf("snapshot misses %s", SnapshotChecksumsFile)
+	}
+	return verifySnapshotSums(expectedSums, sums)
+}
+
+func extractSnapshotFile(r io.Reader, path string, perm os.FileMode) (string, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func verifySnapshotSums(expectedSums []byte, sums map[string]string) error {
+	expected := make(map[string]string, len(sums))
+	scanner := bufio.NewScanner(bytes.NewReader(expectedSums))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "  ", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid %s line %q", SnapshotChecksumsFile, scanner.Text())
+		}
+		expected[parts[1]] = parts[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(expected) != len(sums) {
+		return ErrSnapshotChecksumMismatch
+	}
+	for name, sum := range sums {
+		if expected[name] != sum {
+			return ErrSnapshotChecksumMismatch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package gorocksdb
+
+import (
+	"archive/tar"
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestExportImportSnapshot(t *testing.T) {
+	db := newTestDB(t, "TestExportImportSnapshot", nil)
+	defer db.Close()
+
+	givenKeys := [][]byte{[]byte("key1"), []byte("key2"), []byte("key3")}
+	givenVal := []byte("val")
+	wo := NewDefaultWriteOptions()
+	for _, k := range givenKeys {
+		require.NoError(t, db.Put(wo, k, givenVal))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, db.ExportSnapshot(&buf))
+
+	dir, err := ioutil.TempDir("", "gorocksdb-import-snapshot")
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(dir))
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ImportSnapshot(bytes.NewReader(buf.Bytes()), dir))
+	require.Error(t, ImportSnapshot(bytes.NewReader(buf.Bytes()), dir))
+
+	dbImport, err := OpenDb(NewDefaultOptions(), dir)
+	require.NoError(t, err)
+	defer dbImport.Close()
+
+	ro := NewDefaultReadOptions()
+	for _, k := range givenKeys {
+		value, err := dbImport.GetBytes(ro, k)
+		require.NoError(t, err)
+		require.Equal(t, givenVal, value)
+	}
+}
+
+func TestImportSnapshotCorrupted(t *testing.T) {
+	db := newTestDB(t, "TestImportSnapshotCorrupted", nil)
+	defer db.Close()
+	require.NoError(t, db.Put(NewDefaultWriteOptions(), []byte("key"), []byte("value")))
+
+	var buf bytes.Buffer
+	require.NoError(t, db.ExportSnapshot(&buf))
+
+	dir, err := ioutil.TempDir("", "gorocksdb-import-snapshot-corrupted")
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(dir))
+	defer os.RemoveAll(dir)
+
+	// rewrite the archive with the first byte of each file flipped.
+	var corrupted bytes.Buffer
+	tr := tar.NewReader(&buf)
+	tw := tar.NewWriter(&corrupted)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		if hdr.Name != SnapshotChecksumsFile && len(content) > 0 {
+			content[0] ^= 0xff
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	require.Equal(t, ErrSnapshotChecksumMismatch, ImportSnapshot(&corrupted, dir))
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+}
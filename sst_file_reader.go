@@ -0,0 +1,92 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "sst_file_reader_extension.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// SstFileReader reads sst files, for example to check
+// external files before they are ingested.
+type SstFileReader struct {
+	c *C.gorocksdb_sstfilereader_t
+}
+
+// NewSstFileReader creates an SstFileReader object.
+// The comparator of opts is used to read the file, it should
+// be the comparator of the column family the file is ingested into.
+func NewSstFileReader(opts *Options) *SstFileReader {
+	return &SstFileReader{c: C.gorocksdb_sstfilereader_create(opts.c)}
+}
+
+// Open opens the file located at path.
+func (r *SstFileReader) Open(path string) error {
+	var (
+		cErr  *C.char
+		cPath = C.CString(path)
+	)
+	defer C.free(unsafe.Pointer(cPath))
+	C.gorocksdb_sstfilereader_open(r.c, cPath, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// NewIterator returns an Iterator over the entries of the file.
+// The iterator has to be closed before the reader is destroyed.
+// If the reader is not open, the iterator is invalid and its Err returns an error.
+func (r *SstFileReader) NewIterator(opts *ReadOptions) *Iterator {
+	cIter := C.gorocksdb_sstfilereader_new_iterator(r.c, opts.c)
+	return NewNativeIterator(unsafe.Pointer(cIter))
+}
+
+// VerifyChecksum verifies the checksums of all blocks of the file.
+// It returns an error if the reader is not open.
+func (r *SstFileReader) VerifyChecksum() error {
+	var cErr *C.char
+	C.gorocksdb_sstfilereader_verify_checksum(r.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetTableProperties returns the properties of the file,
+// or nil if the reader is not open.
+func (r *SstFileReader) GetTableProperties() *TableProperties {
+	var cProps C.gorocksdb_tableproperties_t
+	ref := C.gorocksdb_sstfilereader_get_table_properties(r.c, &cProps)
+	if ref == nil {
+		return nil
+	}
+	defer C.gorocksdb_tableproperties_ref_destroy(ref)
+	props := tablePropertiesFromC(&cProps)
+	return &props
+}
+
+// VerifyComparator returns an error if the file was not written
+// with the comparator with name comparatorName.
+func (r *SstFileReader) VerifyComparator(comparatorName string) error {
+	props := r.GetTableProperties()
+	if props == nil {
+		return errors.New("sst file reader is not open")
+	}
+	if props.ComparatorName != comparatorName {
+		return fmt.Errorf("sst file comparator %s does not match %s", props.ComparatorName, comparatorName)
+	}
+	return nil
+}
+
+// Destroy destroys the SstFileReader object.
+func (r *SstFileReader) Destroy() {
+	C.gorocksdb_sstfilereader_destroy(r.c)
+	r.c = nil
+}
@@ -0,0 +1,74 @@
+#include "sst_file_reader_extension.h"
+
+#include <stdlib.h>
+#include "rocksdb_internal.h"
+
+using rocksdb::SstFileReader;
+
+struct gorocksdb_sstfilereader_t {
+	SstFileReader* rep;
+	// SstFileReader dereferences its table reader, which is only set by a successful Open
+	bool opened;
+};
+
+static rocksdb::Status not_open_status() {
+	return rocksdb::Status::InvalidArgument("sst file reader is not open");
+}
+
+extern "C" {
+
+
+gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(const rocksdb_options_t* opts) {
+	gorocksdb_sstfilereader_t* reader = new gorocksdb_sstfilereader_t;
+	reader->rep = new SstFileReader(opts->rep);
+	reader->opened = false;
+	return reader;
+}
+
+void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader, const char* file_path, char** errptr) {
+	reader->opened = !SaveError(errptr, reader->rep->Open(file_path));
+}
+
+rocksdb_iterator_t* gorocksdb_sstfilereader_new_iterator(
+	gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* opts) {
+
+	rocksdb_iterator_t* iter = new rocksdb_iterator_t;
+	if (!reader->opened) {
+		iter->rep = rocksdb::NewErrorIterator(not_open_status());
+		return iter;
+	}
+	iter->rep = reader->rep->NewIterator(opts->rep);
+	return iter;
+}
+
+void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader, char** errptr) {
+	if (!reader->opened) {
+		SaveError(errptr, not_open_status());
+		return;
+	}
+	SaveError(errptr, reader->rep->VerifyChecksum());
+}
+
+gorocksdb_tableproperties_ref_t* gorocksdb_sstfilereader_get_table_properties(
+	gorocksdb_sstfilereader_t* reader, gorocksdb_tableproperties_t* props) {
+
+	if (!reader->opened) {
+		return NULL;
+	}
+	std::shared_ptr<const rocksdb::TableProperties> rep = reader->rep->GetTableProperties();
+	if (!rep) {
+		return NULL;
+	}
+	gorocksdb_tableproperties_ref_t* ref = new gorocksdb_tableproperties_ref_t;
+	ref->rep = rep;
+	gorocksdb_set_tableproperties(props, *ref->rep);
+	return ref;
+}
+
+void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader) {
+	delete reader->rep;
+	delete reader;
+}
+
+
+}
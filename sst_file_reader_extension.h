@@ -0,0 +1,32 @@
+#include "table_properties_extension.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+typedef struct gorocksdb_sstfilereader_t gorocksdb_sstfilereader_t;
+
+gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(const rocksdb_options_t* opts);
+
+void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader, const char* file_path, char** errptr);
+
+// Until the reader is open, the iterator is invalid with an error status
+// and verify_checksum fails.
+rocksdb_iterator_t* gorocksdb_sstfilereader_new_iterator(
+	gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* opts);
+
+void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader, char** errptr);
+
+// The strings of props point into the returned reference,
+// which has to be freed with gorocksdb_tableproperties_ref_destroy.
+// It returns NULL if the reader is not open.
+gorocksdb_tableproperties_ref_t* gorocksdb_sstfilereader_get_table_properties(
+	gorocksdb_sstfilereader_t* reader, gorocksdb_tableproperties_t* props);
+
+void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
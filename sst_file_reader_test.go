@@ -0,0 +1,62 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSstFileReader(t *testing.T) {
+	envOpts := NewDefaultEnvOptions()
+	opts := NewDefaultOptions()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-reader-test")
+	require.NoError(t, err)
+	defer os.Remove(filePath.Name())
+
+	keys := []string{"aaa", "bbb", "ccc"}
+	require.NoError(t, w.Open(filePath.Name()))
+	for _, key := range keys {
+		require.NoError(t, w.Put([]byte(key), []byte(key+"Value")))
+	}
+	_, err = w.Finish()
+	require.NoError(t, err)
+
+	r := NewSstFileReader(opts)
+	defer r.Destroy()
+	require.Nil(t, r.GetTableProperties())
+	require.Error(t, r.VerifyChecksum())
+	closedItr := r.NewIterator(NewDefaultReadOptions())
+	closedItr.SeekToFirst()
+	require.False(t, closedItr.Valid())
+	require.Error(t, closedItr.Err())
+	closedItr.Close()
+	require.Error(t, r.Open(filePath.Name()+".missing"))
+	require.Nil(t, r.GetTableProperties())
+	require.NoError(t, r.Open(filePath.Name()))
+	require.NoError(t, r.VerifyChecksum())
+
+	props := r.GetTableProperties()
+	require.NotNil(t, props)
+	require.Equal(t, uint64(3), props.NumEntries)
+	require.Equal(t, uint64(9), props.RawKeySize)
+	require.Equal(t, uint64(24), props.RawValueSize)
+	require.Equal(t, "leveldb.BytewiseComparator", props.ComparatorName)
+	require.NotEmpty(t, props.CompressionName)
+	require.NoError(t, r.VerifyComparator("leveldb.BytewiseComparator"))
+	require.Error(t, r.VerifyComparator("rocksdb.ReverseBytewiseComparator"))
+
+	itr := r.NewIterator(NewDefaultReadOptions())
+	i := 0
+	for itr.SeekToFirst(); itr.Valid(); itr.Next() {
+		require.Equal(t, keys[i], string(itr.Key()))
+		require.Equal(t, keys[i]+"Value", string(itr.Value()))
+		i++
+	}
+	require.NoError(t, itr.Err())
+	require.Equal(t, len(keys), i)
+	itr.Close()
+}
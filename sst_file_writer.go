@@ -2,6 +2,7 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "sst_file_writer_extension.h"
 import "C"
 
 import (
@@ -15,12 +16,37 @@ type SSTFileWriter struct {
 	c *C.rocksdb_sstfilewriter_t
 }
 
+// ExternalSstFileInfo describes a file written by an SSTFileWriter.
+type ExternalSstFileInfo struct {
+	FilePath string
+	// SmallestKey and LargestKey are the user keys of the point entries.
+	SmallestKey []byte
+	LargestKey  []byte
+	// SmallestRangeDelKey and LargestRangeDelKey are the bounds of the range deletions.
+	SmallestRangeDelKey []byte
+	LargestRangeDelKey  []byte
+	FileSize            uint64
+	NumEntries          uint64
+	NumRangeDelEntries  uint64
+}
+
 // NewSSTFileWriter creates an SSTFileWriter object.
+// The comparator of dbOpts has to match the one of the column family
+// the file is ingested into.
 func NewSSTFileWriter(opts *EnvOptions, dbOpts *Options) *SSTFileWriter {
 	c := C.rocksdb_sstfilewriter_create(opts.c, dbOpts.c)
 	return &SSTFileWriter{c: c}
 }
 
+// NewSSTFileWriterCF creates an SSTFileWriter object for the column family cf,
+// cfOpts should be the options cf was opened with.
+// The column family is stored in the file, ingesting it into another
+// column family fails.
+func NewSSTFileWriterCF(opts *EnvOptions, cfOpts *Options, cf *ColumnFamilyHandle) *SSTFileWriter {
+	c := C.gorocksdb_sstfilewriter_create_cf(opts.c, cfOpts.c, cf.c)
+	return &SSTFileWriter{c: c}
+}
+
 // Open prepares SstFileWriter to write into file located at "path".
 func (w *SSTFileWriter) Open(path string) error {
 	var (
@@ -38,11 +64,19 @@ func (w *SSTFileWriter) Open(path string) error {
 
 // Add adds key, value to currently opened file.
 // REQUIRES: key is after any previously added key according to comparator.
+//
+// Deprecated: use Put.
 func (w *SSTFileWriter) Add(key, value []byte) error {
+	return w.Put(key, value)
+}
+
+// Put adds key, value to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Put(key, value []byte) error {
 	cKey := byteToChar(key)
 	cValue := byteToChar(value)
 	var cErr *C.char
-	C.rocksdb_sstfilewriter_add(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	C.rocksdb_sstfilewriter_put(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
 		return errors.New(C.GoString(cErr))
@@ -50,10 +84,26 @@ func (w *SSTFileWriter) Add(key, value []byte) error {
 	return nil
 }
 
-// Finish finishes writing to sst file and close file.
-func (w *SSTFileWriter) Finish() error {
+// Merge adds a merge of key with value to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Merge(key, value []byte) error {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_merge(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Delete adds a deletion of key to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Delete(key []byte) error {
+	cKey := byteToChar(key)
 	var cErr *C.char
-	C.rocksdb_sstfilewriter_finish(w.c, &cErr)
+	C.rocksdb_sstfilewriter_delete(w.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
 		return errors.New(C.GoString(cErr))
@@ -61,6 +111,54 @@ func (w *SSTFileWriter) Finish() error {
 	return nil
 }
 
+// DeleteRange adds a deletion of the keys in [beginKey, endKey) to currently opened file.
+// Unlike the point entries, the range deletions may be added in any order.
+func (w *SSTFileWriter) DeleteRange(beginKey, endKey []byte) error {
+	cBeginKey := byteToChar(beginKey)
+	cEndKey := byteToChar(endKey)
+	var cErr *C.char
+	C.gorocksdb_sstfilewriter_delete_range(
+		w.c, cBeginKey, C.size_t(len(beginKey)), cEndKey, C.size_t(len(endKey)), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// FileSize returns the current size of the file.
+func (w *SSTFileWriter) FileSize() uint64 {
+	var cSize C.uint64_t
+	C.rocksdb_sstfilewriter_file_size(w.c, &cSize)
+	return uint64(cSize)
+}
+
+// Finish finishes writing to sst file and close file.
+// It returns the information about the written file.
+func (w *SSTFileWriter) Finish() (*ExternalSstFileInfo, error) {
+	var (
+		cErr  *C.char
+		cInfo C.gorocksdb_external_sst_file_info_t
+	)
+	C.gorocksdb_sstfilewriter_finish_with_info(w.c, &cInfo, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_external_sst_file_info_destroy(&cInfo)
+
+	return &ExternalSstFileInfo{
+		FilePath:            charToString(cInfo.file_path, cInfo.file_path_len),
+		SmallestKey:         C.GoBytes(unsafe.Pointer(cInfo.smallest_key), C.int(cInfo.smallest_key_len)),
+		LargestKey:          C.GoBytes(unsafe.Pointer(cInfo.largest_key), C.int(cInfo.largest_key_len)),
+		SmallestRangeDelKey: C.GoBytes(unsafe.Pointer(cInfo.smallest_range_del_key), C.int(cInfo.smallest_range_del_key_len)),
+		LargestRangeDelKey:  C.GoBytes(unsafe.Pointer(cInfo.largest_range_del_key), C.int(cInfo.largest_range_del_key_len)),
+		FileSize:            uint64(cInfo.file_size),
+		NumEntries:          uint64(cInfo.num_entries),
+		NumRangeDelEntries:  uint64(cInfo.num_range_del_entries),
+	}, nil
+}
+
 // Destroy destroys the SSTFileWriter object.
 func (w *SSTFileWriter) Destroy() {
 	C.rocksdb_sstfilewriter_destroy(w.c)
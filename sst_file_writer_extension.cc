@@ -0,0 +1,65 @@
+#include "sst_file_writer_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include <string>
+#include "rocksdb_internal.h"
+
+using rocksdb::ExternalSstFileInfo;
+using rocksdb::Slice;
+using rocksdb::SstFileWriter;
+
+extern "C" {
+
+
+rocksdb_sstfilewriter_t* gorocksdb_sstfilewriter_create_cf(
+	const rocksdb_envoptions_t* env, const rocksdb_options_t* io_options,
+	rocksdb_column_family_handle_t* column_family) {
+
+	rocksdb_sstfilewriter_t* writer = new rocksdb_sstfilewriter_t;
+	writer->rep = new SstFileWriter(
+		env->rep, io_options->rep, column_family == NULL ? nullptr : column_family->rep);
+	return writer;
+}
+
+void gorocksdb_sstfilewriter_delete_range(
+	rocksdb_sstfilewriter_t* writer,
+	const char* begin_key, size_t begin_key_len,
+	const char* end_key, size_t end_key_len, char** errptr) {
+
+	SaveError(errptr, writer->rep->DeleteRange(Slice(begin_key, begin_key_len), Slice(end_key, end_key_len)));
+}
+
+static void copy_string(const std::string& s, char** c, size_t* len) {
+	*c = static_cast<char*>(malloc(s.size()));
+	memcpy(*c, s.data(), s.size());
+	*len = s.size();
+}
+
+void gorocksdb_sstfilewriter_finish_with_info(
+	rocksdb_sstfilewriter_t* writer, gorocksdb_external_sst_file_info_t* info, char** errptr) {
+
+	ExternalSstFileInfo file_info;
+	if (SaveError(errptr, writer->rep->Finish(&file_info))) {
+		return;
+	}
+	copy_string(file_info.file_path, &info->file_path, &info->file_path_len);
+	copy_string(file_info.smallest_key, &info->smallest_key, &info->smallest_key_len);
+	copy_string(file_info.largest_key, &info->largest_key, &info->largest_key_len);
+	copy_string(file_info.smallest_range_del_key, &info->smallest_range_del_key, &info->smallest_range_del_key_len);
+	copy_string(file_info.largest_range_del_key, &info->largest_range_del_key, &info->largest_range_del_key_len);
+	info->file_size = file_info.file_size;
+	info->num_entries = file_info.num_entries;
+	info->num_range_del_entries = file_info.num_range_del_entries;
+}
+
+void gorocksdb_external_sst_file_info_destroy(gorocksdb_external_sst_file_info_t* info) {
+	free(info->file_path);
+	free(info->smallest_key);
+	free(info->largest_key);
+	free(info->smallest_range_del_key);
+	free(info->largest_range_del_key);
+}
+
+
+}
@@ -0,0 +1,43 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+typedef struct {
+	char* file_path;
+	size_t file_path_len;
+	char* smallest_key;
+	size_t smallest_key_len;
+	char* largest_key;
+	size_t largest_key_len;
+	char* smallest_range_del_key;
+	size_t smallest_range_del_key_len;
+	char* largest_range_del_key;
+	size_t largest_range_del_key_len;
+	uint64_t file_size;
+	uint64_t num_entries;
+	uint64_t num_range_del_entries;
+} gorocksdb_external_sst_file_info_t;
+
+// column_family may be NULL, it is stored in the file and checked on ingestion.
+rocksdb_sstfilewriter_t* gorocksdb_sstfilewriter_create_cf(
+	const rocksdb_envoptions_t* env, const rocksdb_options_t* io_options,
+	rocksdb_column_family_handle_t* column_family);
+
+void gorocksdb_sstfilewriter_delete_range(
+	rocksdb_sstfilewriter_t* writer,
+	const char* begin_key, size_t begin_key_len,
+	const char* end_key, size_t end_key_len, char** errptr);
+
+// The info has to be freed with gorocksdb_external_sst_file_info_destroy
+// if no error is returned.
+void gorocksdb_sstfilewriter_finish_with_info(
+	rocksdb_sstfilewriter_t* writer, gorocksdb_external_sst_file_info_t* info, char** errptr);
+
+void gorocksdb_external_sst_file_info_destroy(gorocksdb_external_sst_file_info_t* info);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -0,0 +1,242 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "statistics_extension.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// StatsLevel controls which statistics are collected.
+// Higher levels collect more but are more expensive.
+type StatsLevel int
+
+// Stats levels.
+const (
+	// StatsLevelDisableAll disables all metrics.
+	StatsLevelDisableAll = StatsLevel(0)
+	// StatsLevelExceptTickers is an alias of StatsLevelDisableAll as in rocksdb,
+	// it disables all metrics and not only the tickers.
+	StatsLevelExceptTickers = StatsLevelDisableAll
+	// StatsLevelExceptHistogramOrTimers disables the timer stats and skips histograms.
+	StatsLevelExceptHistogramOrTimers = StatsLevel(2)
+	// StatsLevelExceptTimers skips the timer stats.
+	StatsLevelExceptTimers = StatsLevel(3)
+	// StatsLevelExceptDetailedTimers collects all but the time inside the mutex lock
+	// and the compression time.
+	StatsLevelExceptDetailedTimers = StatsLevel(4)
+	// StatsLevelExceptTimeForMutex collects all but the time inside the mutex lock.
+	StatsLevelExceptTimeForMutex = StatsLevel(5)
+	// StatsLevelAll collects all stats, including the mutex operation time.
+	StatsLevelAll = StatsLevel(6)
+)
+
+// Ticker is the name of a counter of the statistics.
+type Ticker string
+
+// Commonly used tickers, see rocksdb/statistics.h for all.
+const (
+	TickerBlockCacheMiss       = Ticker("rocksdb.block.cache.miss")
+	TickerBlockCacheHit        = Ticker("rocksdb.block.cache.hit")
+	TickerBlockCacheAdd        = Ticker("rocksdb.block.cache.add")
+	TickerBlockCacheIndexMiss  = Ticker("rocksdb.block.cache.index.miss")
+	TickerBlockCacheIndexHit   = Ticker("rocksdb.block.cache.index.hit")
+	TickerBlockCacheFilterMiss = Ticker("rocksdb.block.cache.filter.miss")
+	TickerBlockCacheFilterHit  = Ticker("rocksdb.block.cache.filter.hit")
+	TickerBlockCacheDataMiss   = Ticker("rocksdb.block.cache.data.miss")
+	TickerBlockCacheDataHit    = Ticker("rocksdb.block.cache.data.hit")
+	TickerBloomFilterUseful    = Ticker("rocksdb.bloom.filter.useful")
+	TickerMemtableHit          = Ticker("rocksdb.memtable.hit")
+	TickerMemtableMiss         = Ticker("rocksdb.memtable.miss")
+	TickerGetHitL0             = Ticker("rocksdb.l0.hit")
+	TickerGetHitL1             = Ticker("rocksdb.l1.hit")
+	TickerGetHitL2AndUp        = Ticker("rocksdb.l2andup.hit")
+	TickerNumberKeysWritten    = Ticker("rocksdb.number.keys.written")
+	TickerNumberKeysRead       = Ticker("rocksdb.number.keys.read")
+	TickerNumberKeysUpdated    = Ticker("rocksdb.number.keys.updated")
+	TickerBytesWritten         = Ticker("rocksdb.bytes.written")
+	TickerBytesRead            = Ticker("rocksdb.bytes.read")
+	TickerNumberDBSeek         = Ticker("rocksdb.number.db.seek")
+	TickerNumberDBNext         = Ticker("rocksdb.number.db.next")
+	TickerNumberDBPrev         = Ticker("rocksdb.number.db.prev")
+	TickerStallMicros          = Ticker("rocksdb.stall.micros")
+	TickerCompactReadBytes     = Ticker("rocksdb.compact.read.bytes")
+	TickerCompactWriteBytes    = Ticker("rocksdb.compact.write.bytes")
+	TickerFlushWriteBytes      = Ticker("rocksdb.flush.write.bytes")
+	TickerWALFileBytes         = Ticker("rocksdb.wal.bytes")
+	TickerWALFileSynced        = Ticker("rocksdb.wal.synced")
+	TickerNoFileOpens          = Ticker("rocksdb.no.file.opens")
+)
+
+// Histogram is the name of a histogram of the statistics.
+type Histogram string
+
+// Commonly used histograms, see rocksdb/statistics.h for all.
+const (
+	HistogramDBGet          = Histogram("rocksdb.db.get.micros")
+	HistogramDBWrite        = Histogram("rocksdb.db.write.micros")
+	HistogramDBSeek         = Histogram("rocksdb.db.seek.micros")
+	HistogramDBMultiGet     = Histogram("rocksdb.db.multiget.micros")
+	HistogramCompactionTime = Histogram("rocksdb.compaction.times.micros")
+	HistogramFlushTime      = Histogram("rocksdb.db.flush.micros")
+	HistogramWALFileSync    = Histogram("rocksdb.wal.file.sync.micros")
+	HistogramSSTRead        = Histogram("rocksdb.sst.read.micros")
+	HistogramBytesPerRead   = Histogram("rocksdb.bytes.per.read")
+	HistogramBytesPerWrite  = Histogram("rocksdb.bytes.per.write")
+)
+
+// HistogramData contains the percentiles and aggregates of a histogram.
+type HistogramData struct {
+	Median            float64
+	Percentile95      float64
+	Percentile99      float64
+	Average           float64
+	StandardDeviation float64
+	Max               float64
+	Count             uint64
+	Sum               uint64
+}
+
+// StatisticsSnapshot contains the values of all tickers and histograms
+// at a point in time.
+type StatisticsSnapshot struct {
+	Tickers    map[Ticker]uint64
+	Histograms map[Histogram]HistogramData
+}
+
+// Statistics collects the metrics of one or more DBs.
+type Statistics struct {
+	c *C.gorocksdb_statistics_t
+}
+
+// NewStatistics creates new Statistics.
+func NewStatistics() *Statistics {
+	return NewNativeStatistics(C.gorocksdb_statistics_create())
+}
+
+// NewNativeStatistics creates a Statistics object.
+func NewNativeStatistics(c *C.gorocksdb_statistics_t) *Statistics {
+	return &Statistics{c}
+}
+
+// SetStatistics sets the statistics the DBs opened with these options
+// collect their metrics in.
+// Default: nil
+func (opts *Options) SetStatistics(stats *Statistics) {
+	C.gorocksdb_options_set_statistics(opts.c, stats.c)
+}
+
+// GetStatistics returns the statistics of the options, for example
+// after EnableStatistics, or nil if statistics are not enabled.
+// The returned Statistics have to be destroyed.
+func (opts *Options) GetStatistics() *Statistics {
+	c := C.gorocksdb_options_get_statistics(opts.c)
+	if c == nil {
+		return nil
+	}
+	return NewNativeStatistics(c)
+}
+
+// SetStatsLevel sets the level of the collected statistics.
+// Default: StatsLevelExceptDetailedTimers
+func (s *Statistics) SetStatsLevel(level StatsLevel) {
+	C.gorocksdb_statistics_set_stats_level(s.c, C.int(level))
+}
+
+// GetStatsLevel returns the level of the collected statistics.
+func (s *Statistics) GetStatsLevel() StatsLevel {
+	return StatsLevel(C.gorocksdb_statistics_get_stats_level(s.c))
+}
+
+// TickerCount returns the value of the ticker.
+// Tickers unknown to the rocksdb version return 0.
+func (s *Statistics) TickerCount(ticker Ticker) uint64 {
+	var count C.uint64_t
+	name := []byte(ticker)
+	if C.gorocksdb_statistics_ticker_count(s.c, byteToChar(name), C.size_t(len(name)), &count) == 0 {
+		return 0
+	}
+	return uint64(count)
+}
+
+// Histogram returns the data of the histogram.
+// Histograms unknown to the rocksdb version return empty data.
+func (s *Statistics) Histogram(histogram Histogram) HistogramData {
+	var cData C.gorocksdb_histogram_data_t
+	name := []byte(histogram)
+	if C.gorocksdb_statistics_histogram_data(s.c, byteToChar(name), C.size_t(len(name)), &cData) == 0 {
+		return HistogramData{}
+	}
+	return histogramDataFromC(&cData)
+}
+
+// Snapshot returns the values of all tickers and histograms.
+func (s *Statistics) Snapshot() *StatisticsSnapshot {
+	numTickers := int(C.gorocksdb_statistics_num_tickers())
+	tickerNames := make([]*C.char, numTickers)
+	tickerNamesLens := make([]C.size_t, numTickers)
+	tickerCounts := make([]C.uint64_t, numTickers)
+	if numTickers > 0 {
+		C.gorocksdb_statistics_all_tickers(s.c, &tickerNames[0], &tickerNamesLens[0], &tickerCounts[0])
+	}
+
+	numHistograms := int(C.gorocksdb_statistics_num_histograms())
+	histogramNames := make([]*C.char, numHistograms)
+	histogramNamesLens := make([]C.size_t, numHistograms)
+	histogramData := make([]C.gorocksdb_histogram_data_t, numHistograms)
+	if numHistograms > 0 {
+		C.gorocksdb_statistics_all_histograms(s.c, &histogramNames[0], &histogramNamesLens[0], &histogramData[0])
+	}
+
+	snapshot := &StatisticsSnapshot{
+		Tickers:    make(map[Ticker]uint64, numTickers),
+		Histograms: make(map[Histogram]HistogramData, numHistograms),
+	}
+	for i := 0; i < numTickers; i++ {
+		snapshot.Tickers[Ticker(charToString(tickerNames[i], tickerNamesLens[i]))] = uint64(tickerCounts[i])
+	}
+	for i := 0; i < numHistograms; i++ {
+		snapshot.Histograms[Histogram(charToString(histogramNames[i], histogramNamesLens[i]))] = histogramDataFromC(&histogramData[i])
+	}
+	return snapshot
+}
+
+// Reset resets all tickers and histograms.
+func (s *Statistics) Reset() error {
+	var cErr *C.char
+	C.gorocksdb_statistics_reset(s.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// String returns the statistics as human readable text.
+func (s *Statistics) String() string {
+	cValue := C.gorocksdb_statistics_to_string(s.c)
+	defer C.free(unsafe.Pointer(cValue))
+	return C.GoString(cValue)
+}
+
+// Destroy deallocates the Statistics object.
+// The statistics stay alive as long as they are used by options or DBs.
+func (s *Statistics) Destroy() {
+	C.gorocksdb_statistics_destroy(s.c)
+	s.c = nil
+}
+
+func histogramDataFromC(c *C.gorocksdb_histogram_data_t) HistogramData {
+	return HistogramData{
+		Median:            float64(c.median),
+		Percentile95:      float64(c.percentile95),
+		Percentile99:      float64(c.percentile99),
+		Average:           float64(c.average),
+		StandardDeviation: float64(c.standard_deviation),
+		Max:               float64(c.max),
+		Count:             uint64(c.count),
+		Sum:               uint64(c.sum),
+	}
+}
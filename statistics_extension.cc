@@ -0,0 +1,199 @@
+#include "statistics_extension.h"
+
+#include <stdlib.h>
+#include <string.h>
+#include <memory>
+#include <string>
+#include <unordered_map>
+#include "rocksdb/statistics.h"
+#include "rocksdb_internal.h"
+
+using rocksdb::HistogramData;
+using rocksdb::HistogramsNameMap;
+using rocksdb::Statistics;
+using rocksdb::StatsLevel;
+using rocksdb::Status;
+using rocksdb::TickersNameMap;
+
+struct gorocksdb_statistics_t {
+	std::shared_ptr<Statistics> rep;
+};
+
+// The enum values of tickers and histograms differ between rocksdb versions,
+// so they are looked up by name.
+static const std::unordered_map<std::string, uint32_t>& ticker_ids() {
+	static const std::unordered_map<std::string, uint32_t>* ids = [] {
+		auto m = new std::unordered_map<std::string, uint32_t>();
+		for (const auto& t : TickersNameMap) {
+			(*m)[t.second] = t.first;
+		}
+		return m;
+	}();
+	return *ids;
+}
+
+static const std::unordered_map<std::string, uint32_t>& histogram_ids() {
+	static const std::unordered_map<std::string, uint32_t>* ids = [] {
+		auto m = new std::unordered_map<std::string, uint32_t>();
+		for (const auto& h : HistogramsNameMap) {
+			(*m)[h.second] = h.first;
+		}
+		return m;
+	}();
+	return *ids;
+}
+
+static void set_histogram_data(gorocksdb_histogram_data_t* dst, const HistogramData& src) {
+	dst->median = src.median;
+	dst->percentile95 = src.percentile95;
+	dst->percentile99 = src.percentile99;
+	dst->average = src.average;
+	dst->standard_deviation = src.standard_deviation;
+	dst->max = src.max;
+	dst->count = src.count;
+	dst->sum = src.sum;
+}
+
+// Values of the Go StatsLevel, the values of the rocksdb enum differ between versions.
+// kExceptTickers is an alias of kDisableAll and has no value of its own.
+static StatsLevel to_stats_level(int level) {
+	switch (level) {
+	case 0:
+		return StatsLevel::kDisableAll;
+	case 2:
+		return StatsLevel::kExceptHistogramOrTimers;
+	case 3:
+		return StatsLevel::kExceptTimers;
+	case 4:
+		return StatsLevel::kExceptDetailedTimers;
+	case 5:
+		return StatsLevel::kExceptTimeForMutex;
+	default:
+		return StatsLevel::kAll;
+	}
+}
+
+static int from_stats_level(StatsLevel level) {
+	switch (level) {
+	case StatsLevel::kDisableAll:
+		return 0;
+	case StatsLevel::kExceptHistogramOrTimers:
+		return 2;
+	case StatsLevel::kExceptTimers:
+		return 3;
+	case StatsLevel::kExceptDetailedTimers:
+		return 4;
+	case StatsLevel::kExceptTimeForMutex:
+		return 5;
+	default:
+		return 6;
+	}
+}
+
+extern "C" {
+
+
+gorocksdb_statistics_t* gorocksdb_statistics_create() {
+	gorocksdb_statistics_t* stats = new gorocksdb_statistics_t;
+	stats->rep = rocksdb::CreateDBStatistics();
+	return stats;
+}
+
+void gorocksdb_statistics_destroy(gorocksdb_statistics_t* stats) {
+	delete stats;
+}
+
+void gorocksdb_options_set_statistics(rocksdb_options_t* opts, gorocksdb_statistics_t* stats) {
+	opts->rep.statistics = stats->rep;
+}
+
+gorocksdb_statistics_t* gorocksdb_options_get_statistics(rocksdb_options_t* opts) {
+	if (!opts->rep.statistics) {
+		return NULL;
+	}
+	gorocksdb_statistics_t* stats = new gorocksdb_statistics_t;
+	stats->rep = opts->rep.statistics;
+	return stats;
+}
+
+void gorocksdb_statistics_set_stats_level(gorocksdb_statistics_t* stats, int level) {
+	stats->rep->set_stats_level(to_stats_level(level));
+}
+
+int gorocksdb_statistics_get_stats_level(gorocksdb_statistics_t* stats) {
+	return from_stats_level(stats->rep->get_stats_level());
+}
+
+unsigned char gorocksdb_statistics_ticker_count(
+	gorocksdb_statistics_t* stats, const char* name, size_t name_len, uint64_t* count) {
+
+	const auto& ids = ticker_ids();
+	auto it = ids.find(std::string(name, name_len));
+	if (it == ids.end()) {
+		return 0;
+	}
+	*count = stats->rep->getTickerCount(it->second);
+	return 1;
+}
+
+unsigned char gorocksdb_statistics_histogram_data(
+	gorocksdb_statistics_t* stats, const char* name, size_t name_len, gorocksdb_histogram_data_t* data) {
+
+	const auto& ids = histogram_ids();
+	auto it = ids.find(std::string(name, name_len));
+	if (it == ids.end()) {
+		return 0;
+	}
+	HistogramData hd;
+	stats->rep->histogramData(it->second, &hd);
+	set_histogram_data(data, hd);
+	return 1;
+}
+
+size_t gorocksdb_statistics_num_tickers() {
+	return TickersNameMap.size();
+}
+
+size_t gorocksdb_statistics_num_histograms() {
+	return HistogramsNameMap.size();
+}
+
+void gorocksdb_statistics_all_tickers(
+	gorocksdb_statistics_t* stats, char** names, size_t* names_lens, uint64_t* counts) {
+
+	size_t i = 0;
+	for (const auto& t : TickersNameMap) {
+		names[i] = const_cast<char*>(t.second.data());
+		names_lens[i] = t.second.size();
+		counts[i] = stats->rep->getTickerCount(t.first);
+		i++;
+	}
+}
+
+void gorocksdb_statistics_all_histograms(
+	gorocksdb_statistics_t* stats, char** names, size_t* names_lens, gorocksdb_histogram_data_t* data) {
+
+	size_t i = 0;
+	for (const auto& h : HistogramsNameMap) {
+		names[i] = const_cast<char*>(h.second.data());
+		names_lens[i] = h.second.size();
+		HistogramData hd;
+		stats->rep->histogramData(h.first, &hd);
+		set_histogram_data(&data[i], hd);
+		i++;
+	}
+}
+
+void gorocksdb_statistics_reset(gorocksdb_statistics_t* stats, char** errptr) {
+	Status s = stats->rep->Reset();
+	if (!s.ok()) {
+		*errptr = strdup(s.ToString().c_str());
+	}
+}
+
+char* gorocksdb_statistics_to_string(gorocksdb_statistics_t* stats) {
+	return strdup(stats->rep->ToString().c_str());
+}
+
+
+}
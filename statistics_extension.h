@@ -0,0 +1,60 @@
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+typedef struct gorocksdb_statistics_t gorocksdb_statistics_t;
+
+typedef struct {
+	double median;
+	double percentile95;
+	double percentile99;
+	double average;
+	double standard_deviation;
+	double max;
+	uint64_t count;
+	uint64_t sum;
+} gorocksdb_histogram_data_t;
+
+gorocksdb_statistics_t* gorocksdb_statistics_create();
+
+void gorocksdb_statistics_destroy(gorocksdb_statistics_t* stats);
+
+void gorocksdb_options_set_statistics(rocksdb_options_t* opts, gorocksdb_statistics_t* stats);
+
+// Returns NULL if the options have no statistics.
+gorocksdb_statistics_t* gorocksdb_options_get_statistics(rocksdb_options_t* opts);
+
+void gorocksdb_statistics_set_stats_level(gorocksdb_statistics_t* stats, int level);
+
+int gorocksdb_statistics_get_stats_level(gorocksdb_statistics_t* stats);
+
+// Returns 0 if name is not a ticker.
+unsigned char gorocksdb_statistics_ticker_count(
+	gorocksdb_statistics_t* stats, const char* name, size_t name_len, uint64_t* count);
+
+// Returns 0 if name is not a histogram.
+unsigned char gorocksdb_statistics_histogram_data(
+	gorocksdb_statistics_t* stats, const char* name, size_t name_len, gorocksdb_histogram_data_t* data);
+
+size_t gorocksdb_statistics_num_tickers();
+
+size_t gorocksdb_statistics_num_histograms();
+
+// names, names_lens and counts must have gorocksdb_statistics_num_tickers() elements.
+void gorocksdb_statistics_all_tickers(
+	gorocksdb_statistics_t* stats, char** names, size_t* names_lens, uint64_t* counts);
+
+// names, names_lens and data must have gorocksdb_statistics_num_histograms() elements.
+void gorocksdb_statistics_all_histograms(
+	gorocksdb_statistics_t* stats, char** names, size_t* names_lens, gorocksdb_histogram_data_t* data);
+
+void gorocksdb_statistics_reset(gorocksdb_statistics_t* stats, char** errptr);
+
+char* gorocksdb_statistics_to_string(gorocksdb_statistics_t* stats);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -0,0 +1,56 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStatistics(t *testing.T) {
+	stats := NewStatistics()
+	defer stats.Destroy()
+	stats.SetStatsLevel(StatsLevelExceptTickers)
+	require.Equal(t, StatsLevelDisableAll, stats.GetStatsLevel())
+	stats.SetStatsLevel(StatsLevelAll)
+	require.Equal(t, StatsLevelAll, stats.GetStatsLevel())
+
+	db := newTestDB(t, "TestStatistics", func(opts *Options) {
+		opts.SetStatistics(stats)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, db.Put(wo, []byte("key"), []byte("value")))
+		_, err := db.GetBytes(ro, []byte("key"))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, uint64(10), stats.TickerCount(TickerNumberKeysWritten))
+	require.Equal(t, uint64(10), stats.TickerCount(TickerNumberKeysRead))
+	require.Equal(t, uint64(0), stats.TickerCount(Ticker("unknown")))
+
+	hd := stats.Histogram(HistogramDBGet)
+	require.Equal(t, uint64(10), hd.Count)
+	require.True(t, hd.Percentile99 >= hd.Median)
+	require.Equal(t, HistogramData{}, stats.Histogram(Histogram("unknown")))
+
+	snapshot := stats.Snapshot()
+	require.Equal(t, uint64(10), snapshot.Tickers[TickerNumberKeysWritten])
+	require.Equal(t, uint64(10), snapshot.Histograms[HistogramDBGet].Count)
+	require.NotEmpty(t, stats.String())
+
+	require.NoError(t, stats.Reset())
+	require.Equal(t, uint64(0), stats.TickerCount(TickerNumberKeysWritten))
+}
+
+func TestOptionsGetStatistics(t *testing.T) {
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	require.Nil(t, opts.GetStatistics())
+
+	opts.EnableStatistics()
+	stats := opts.GetStatistics()
+	require.NotNil(t, stats)
+	stats.Destroy()
+}
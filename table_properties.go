@@ -0,0 +1,172 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "table_properties_extension.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"unsafe"
+)
+
+// TableProperties contains the properties of a table (sst) file.
+type TableProperties struct {
+	DataSize          uint64
+	IndexSize         uint64
+	FilterSize        uint64
+	RawKeySize        uint64
+	RawValueSize      uint64
+	NumDataBlocks     uint64
+	NumEntries        uint64
+	NumDeletions      uint64
+	NumMergeOperands  uint64
+	NumRangeDeletions uint64
+	FormatVersion     uint64
+	// CreationTime is the unix time in seconds of the oldest key of the
+	// memtable or the oldest input file the table was created from, 0 if unknown.
+	CreationTime uint64
+	// OldestKeyTime is the unix time in seconds of the oldest key, 0 if unknown.
+	OldestKeyTime uint64
+	// FileCreationTime is the unix time in seconds when the file was created, 0 if unknown.
+	FileCreationTime    uint64
+	ColumnFamilyName    string
+	ComparatorName      string
+	MergeOperatorName   string
+	PrefixExtractorName string
+	CompressionName     string
+	// UserCollectedProperties are the properties of the
+	// TablePropertiesCollectors, see Options.AddTablePropertiesCollectorFactory.
+	UserCollectedProperties map[string]string
+}
+
+func tablePropertiesFromC(c *C.gorocksdb_tableproperties_t) TableProperties {
+	return TableProperties{
+		DataSize:                uint64(c.data_size),
+		IndexSize:               uint64(c.index_size),
+		FilterSize:              uint64(c.filter_size),
+		RawKeySize:              uint64(c.raw_key_size),
+		RawValueSize:            uint64(c.raw_value_size),
+		NumDataBlocks:           uint64(c.num_data_blocks),
+		NumEntries:              uint64(c.num_entries),
+		NumDeletions:            uint64(c.num_deletions),
+		NumMergeOperands:        uint64(c.num_merge_operands),
+		NumRangeDeletions:       uint64(c.num_range_deletions),
+		FormatVersion:           uint64(c.format_version),
+		CreationTime:            uint64(c.creation_time),
+		OldestKeyTime:           uint64(c.oldest_key_time),
+		FileCreationTime:        uint64(c.file_creation_time),
+		ColumnFamilyName:        charToString(c.column_family_name, c.column_family_name_len),
+		ComparatorName:          charToString(c.comparator_name, c.comparator_name_len),
+		MergeOperatorName:       charToString(c.merge_operator_name, c.merge_operator_name_len),
+		PrefixExtractorName:     charToString(c.prefix_extractor_name, c.prefix_extractor_name_len),
+		CompressionName:         charToString(c.compression_name, c.compression_name_len),
+		UserCollectedProperties: userCollectedPropertiesFromC(c.user_collected_properties),
+	}
+}
+
+func userCollectedPropertiesFromC(c *C.gorocksdb_user_collected_properties_t) map[string]string {
+	if c == nil {
+		return nil
+	}
+	num := int(C.gorocksdb_user_collected_properties_count(c))
+	props := make(map[string]string, num)
+	if num == 0 {
+		return props
+	}
+	keys := make([]*C.char, num)
+	keysLens := make([]C.size_t, num)
+	values := make([]*C.char, num)
+	valuesLens := make([]C.size_t, num)
+	C.gorocksdb_user_collected_properties_get(c, &keys[0], &keysLens[0], &values[0], &valuesLens[0])
+	for i := range keys {
+		props[charToString(keys[i], keysLens[i])] = charToString(values[i], valuesLens[i])
+	}
+	return props
+}
+
+// GetPropertiesOfAllTables returns the properties of all table files
+// of the default column family, by their path.
+func (db *DB) GetPropertiesOfAllTables() (map[string]*TableProperties, error) {
+	return db.getPropertiesOfAllTables(nil)
+}
+
+// GetPropertiesOfAllTablesCF returns the properties of all table files
+// of a column family, by their path.
+func (db *DB) GetPropertiesOfAllTablesCF(cf *ColumnFamilyHandle) (map[string]*TableProperties, error) {
+	return db.getPropertiesOfAllTables(cf.c)
+}
+
+// GetLiveFilesMetaDataWithProperties returns the live files like
+// GetLiveFilesMetaData with their UserCollectedProperties set.
+// cfs are the handles of all column families of the DB,
+// nil means only the default column family.
+// It reads the properties of all table files and returns an error if
+// a live file belongs to a column family which is not in cfs.
+// A file that is replaced by a concurrent compaction may also fail the call,
+// in which case it can be retried.
+func (db *DB) GetLiveFilesMetaDataWithProperties(cfs []*ColumnFamilyHandle) ([]LiveFileMetadata, error) {
+	// table files are matched by the name of their column family and file.
+	type tableKey struct {
+		columnFamilyName string
+		fileName         string
+	}
+	tables := make(map[tableKey]*TableProperties)
+	addTables := func(props map[string]*TableProperties) {
+		for path, p := range props {
+			tables[tableKey{p.ColumnFamilyName, filepath.Base(path)}] = p
+		}
+	}
+	if cfs == nil {
+		props, err := db.GetPropertiesOfAllTables()
+		if err != nil {
+			return nil, err
+		}
+		addTables(props)
+	}
+	for _, cf := range cfs {
+		props, err := db.GetPropertiesOfAllTablesCF(cf)
+		if err != nil {
+			return nil, err
+		}
+		addTables(props)
+	}
+
+	liveFiles := db.GetLiveFilesMetaData()
+	for i := range liveFiles {
+		lf := &liveFiles[i]
+		p, ok := tables[tableKey{lf.ColumnFamilyName, filepath.Base(lf.Name)}]
+		if !ok {
+			return nil, fmt.Errorf("no table properties for file %s of column family %s", lf.Name, lf.ColumnFamilyName)
+		}
+		lf.UserCollectedProperties = p.UserCollectedProperties
+	}
+	return liveFiles, nil
+}
+
+func (db *DB) getPropertiesOfAllTables(cf *C.rocksdb_column_family_handle_t) (map[string]*TableProperties, error) {
+	var (
+		cErr *C.char
+		cNum C.size_t
+	)
+	collection := C.gorocksdb_get_properties_of_all_tables(db.c, cf, &cNum, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_tableproperties_collection_destroy(collection)
+
+	tables := make(map[string]*TableProperties, int(cNum))
+	for i := C.size_t(0); i < cNum; i++ {
+		var (
+			cPath    *C.char
+			cPathLen C.size_t
+			cProps   C.gorocksdb_tableproperties_t
+		)
+		C.gorocksdb_tableproperties_collection_get(collection, i, &cPath, &cPathLen, &cProps)
+		props := tablePropertiesFromC(&cProps)
+		tables[charToString(cPath, cPathLen)] = &props
+	}
+	return tables, nil
+}
@@ -0,0 +1,160 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "table_properties_collector_extension.h"
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+// EntryType is the type of an entry passed to a TablePropertiesCollector.
+type EntryType int
+
+// Entry types.
+const (
+	EntryPut           = EntryType(0)
+	EntryDelete        = EntryType(1)
+	EntrySingleDelete  = EntryType(2)
+	EntryMerge         = EntryType(3)
+	EntryRangeDeletion = EntryType(4)
+	EntryBlobIndex     = EntryType(5)
+	EntryOther         = EntryType(6)
+)
+
+// A TablePropertiesCollector collects user properties of a table file
+// while it is written by a flush or compaction.
+// The properties are stored in the file and returned in
+// TableProperties.UserCollectedProperties.
+//
+// A collector is used by a single thread only, but multiple collectors
+// are used concurrently.
+type TablePropertiesCollector interface {
+	// AddUserKey is called for each entry added to the table.
+	// key and value are only valid during the call.
+	// fileSize is the current size of the file.
+	AddUserKey(key, value []byte, entryType EntryType, seq uint64, fileSize uint64) error
+
+	// Finish is called when the table is finished and returns the user properties.
+	Finish() (map[string]string, error)
+
+	// Name returns the name of the collector.
+	Name() string
+}
+
+// A TablePropertiesCollectorFactory creates a TablePropertiesCollector
+// for each table file.
+type TablePropertiesCollectorFactory interface {
+	// CreateTablePropertiesCollector creates a collector for a file
+	// of the column family with id columnFamilyID.
+	CreateTablePropertiesCollector(columnFamilyID uint32) TablePropertiesCollector
+
+	// Name returns the name of the factory.
+	Name() string
+}
+
+// Hold references to table properties collector factories.
+var tablePropertiesCollectorFactories = struct {
+	sync.RWMutex
+	factories []TablePropertiesCollectorFactory
+}{}
+
+func registerTablePropertiesCollectorFactory(factory TablePropertiesCollectorFactory) int {
+	tablePropertiesCollectorFactories.Lock()
+	defer tablePropertiesCollectorFactories.Unlock()
+	tablePropertiesCollectorFactories.factories = append(tablePropertiesCollectorFactories.factories, factory)
+	return len(tablePropertiesCollectorFactories.factories) - 1
+}
+
+func getTablePropertiesCollectorFactory(idx C.uintptr_t) TablePropertiesCollectorFactory {
+	tablePropertiesCollectorFactories.RLock()
+	defer tablePropertiesCollectorFactories.RUnlock()
+	return tablePropertiesCollectorFactories.factories[idx]
+}
+
+// tablePropertiesCollectors holds the collectors of the table files
+// which are currently written.
+var tablePropertiesCollectors = struct {
+	sync.RWMutex
+	next       uintptr
+	collectors map[uintptr]TablePropertiesCollector
+}{collectors: make(map[uintptr]TablePropertiesCollector)}
+
+func registerTablePropertiesCollector(collector TablePropertiesCollector) uintptr {
+	tablePropertiesCollectors.Lock()
+	defer tablePropertiesCollectors.Unlock()
+	idx := tablePropertiesCollectors.next
+	tablePropertiesCollectors.next++
+	tablePropertiesCollectors.collectors[idx] = collector
+	return idx
+}
+
+func getTablePropertiesCollector(idx C.uintptr_t) TablePropertiesCollector {
+	tablePropertiesCollectors.RLock()
+	defer tablePropertiesCollectors.RUnlock()
+	return tablePropertiesCollectors.collectors[uintptr(idx)]
+}
+
+// AddTablePropertiesCollectorFactory adds a factory whose collectors collect
+// the user properties of each table file written by the DBs opened
+// with these options.
+// Must be called before opening the DB.
+func (opts *Options) AddTablePropertiesCollectorFactory(factory TablePropertiesCollectorFactory) {
+	idx := registerTablePropertiesCollectorFactory(factory)
+	cName := C.CString(factory.Name())
+	defer C.free(unsafe.Pointer(cName))
+	C.gorocksdb_options_add_tablepropertiescollectorfactory(opts.c, C.uintptr_t(idx), cName)
+}
+
+// errorToChar returns the message of err allocated in the C heap, or nil.
+func errorToChar(err error) *C.char {
+	if err == nil {
+		return nil
+	}
+	return C.CString(err.Error())
+}
+
+//export gorocksdb_tablepropertiescollectorfactory_create
+func gorocksdb_tablepropertiescollectorfactory_create(idx C.uintptr_t, cfID C.uint32_t) C.uintptr_t {
+	collector := getTablePropertiesCollectorFactory(idx).CreateTablePropertiesCollector(uint32(cfID))
+	return C.uintptr_t(registerTablePropertiesCollector(collector))
+}
+
+//export gorocksdb_tablepropertiescollector_name
+func gorocksdb_tablepropertiescollector_name(idx C.uintptr_t) *C.char {
+	return C.CString(getTablePropertiesCollector(idx).Name())
+}
+
+//export gorocksdb_tablepropertiescollector_add_user_key
+func gorocksdb_tablepropertiescollector_add_user_key(
+	idx C.uintptr_t, cKey *C.char, cKeyLen C.size_t, cValue *C.char, cValueLen C.size_t,
+	cEntryType C.int, cSeq C.uint64_t, cFileSize C.uint64_t,
+) *C.char {
+	key := charToByte(cKey, cKeyLen)
+	value := charToByte(cValue, cValueLen)
+	err := getTablePropertiesCollector(idx).AddUserKey(key, value, EntryType(cEntryType), uint64(cSeq), uint64(cFileSize))
+	return errorToChar(err)
+}
+
+//export gorocksdb_tablepropertiescollector_finish
+func gorocksdb_tablepropertiescollector_finish(idx C.uintptr_t, cProps *C.gorocksdb_user_collected_properties_t) *C.char {
+	props, err := getTablePropertiesCollector(idx).Finish()
+	if err != nil {
+		return errorToChar(err)
+	}
+	for k, v := range props {
+		key := []byte(k)
+		value := []byte(v)
+		C.gorocksdb_user_collected_properties_add(cProps,
+			byteToChar(key), C.size_t(len(key)), byteToChar(value), C.size_t(len(value)))
+	}
+	return nil
+}
+
+//export gorocksdb_tablepropertiescollector_destroy
+func gorocksdb_tablepropertiescollector_destroy(idx C.uintptr_t) {
+	tablePropertiesCollectors.Lock()
+	delete(tablePropertiesCollectors.collectors, uintptr(idx))
+	tablePropertiesCollectors.Unlock()
+}
@@ -0,0 +1,118 @@
+#include "table_properties_collector_extension.h"
+
+#include <stdlib.h>
+#include <memory>
+#include <string>
+#include "rocksdb/table_properties.h"
+#include "rocksdb_internal.h"
+
+using rocksdb::EntryType;
+using rocksdb::SequenceNumber;
+using rocksdb::Slice;
+using rocksdb::Status;
+using rocksdb::TablePropertiesCollector;
+using rocksdb::TablePropertiesCollectorFactory;
+using rocksdb::UserCollectedProperties;
+
+// The values of the EntryType enum differ between rocksdb versions.
+static int entry_type(EntryType type) {
+	switch (type) {
+	case rocksdb::kEntryPut:
+		return 0;
+	case rocksdb::kEntryDelete:
+		return 1;
+	case rocksdb::kEntrySingleDelete:
+		return 2;
+	case rocksdb::kEntryMerge:
+		return 3;
+	case rocksdb::kEntryRangeDeletion:
+		return 4;
+	case rocksdb::kEntryBlobIndex:
+		return 5;
+	default:
+		return 6;
+	}
+}
+
+static Status go_status(char* err) {
+	if (err == NULL) {
+		return Status::OK();
+	}
+	Status s = Status::Aborted(err);
+	free(err);
+	return s;
+}
+
+static char* str_data(const Slice& s) {
+	return const_cast<char*>(s.data());
+}
+
+// GoTablePropertiesCollector passes the keys to the
+// TablePropertiesCollector registered in Go at idx.
+class GoTablePropertiesCollector : public TablePropertiesCollector {
+public:
+	explicit GoTablePropertiesCollector(uintptr_t idx) : idx_(idx) {
+		char* name = gorocksdb_tablepropertiescollector_name(idx);
+		name_ = name;
+		free(name);
+	}
+
+	~GoTablePropertiesCollector() override {
+		gorocksdb_tablepropertiescollector_destroy(idx_);
+	}
+
+	Status AddUserKey(const Slice& key, const Slice& value, EntryType type,
+	                  SequenceNumber seq, uint64_t file_size) override {
+		return go_status(gorocksdb_tablepropertiescollector_add_user_key(
+			idx_, str_data(key), key.size(), str_data(value), value.size(),
+			entry_type(type), seq, file_size));
+	}
+
+	Status Finish(UserCollectedProperties* properties) override {
+		return go_status(gorocksdb_tablepropertiescollector_finish(
+			idx_, reinterpret_cast<gorocksdb_user_collected_properties_t*>(properties)));
+	}
+
+	UserCollectedProperties GetReadableProperties() const override {
+		return UserCollectedProperties();
+	}
+
+	const char* Name() const override {
+		return name_.c_str();
+	}
+
+private:
+	uintptr_t idx_;
+	std::string name_;
+};
+
+class GoTablePropertiesCollectorFactory : public TablePropertiesCollectorFactory {
+public:
+	GoTablePropertiesCollectorFactory(uintptr_t idx, const char* name) : idx_(idx), name_(name) {}
+
+	TablePropertiesCollector* CreateTablePropertiesCollector(
+		TablePropertiesCollectorFactory::Context context) override {
+
+		uintptr_t collector_idx = gorocksdb_tablepropertiescollectorfactory_create(idx_, context.column_family_id);
+		return new GoTablePropertiesCollector(collector_idx);
+	}
+
+	const char* Name() const override {
+		return name_.c_str();
+	}
+
+private:
+	uintptr_t idx_;
+	std::string name_;
+};
+
+extern "C" {
+
+
+void gorocksdb_options_add_tablepropertiescollectorfactory(rocksdb_options_t* opts, uintptr_t idx, const char* name) {
+	opts->rep.table_properties_collector_factories.push_back(
+		std::make_shared<GoTablePropertiesCollectorFactory>(idx, name));
+}
+
+
+}
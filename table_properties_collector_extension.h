@@ -0,0 +1,26 @@
+#include "table_properties_extension.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+/* Table properties collector */
+
+// Implemented in Go.
+// The returned names and error messages are freed by the caller.
+extern uintptr_t gorocksdb_tablepropertiescollectorfactory_create(uintptr_t idx, uint32_t column_family_id);
+extern char* gorocksdb_tablepropertiescollector_name(uintptr_t idx);
+extern char* gorocksdb_tablepropertiescollector_add_user_key(
+	uintptr_t idx, char* key, size_t key_len, char* value, size_t value_len,
+	int entry_type, uint64_t seq, uint64_t file_size);
+extern char* gorocksdb_tablepropertiescollector_finish(uintptr_t idx, gorocksdb_user_collected_properties_t* props);
+extern void gorocksdb_tablepropertiescollector_destroy(uintptr_t idx);
+
+void gorocksdb_options_add_tablepropertiescollectorfactory(rocksdb_options_t* opts, uintptr_t idx, const char* name);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
@@ -0,0 +1,122 @@
+package gorocksdb
+
+import (
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablePropertiesCollector(t *testing.T) {
+	db := newTestDB(t, "TestTablePropertiesCollector", func(opts *Options) {
+		opts.AddTablePropertiesCollectorFactory(&mockTablePropertiesCollectorFactory{})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key1"), []byte("val1")))
+	require.NoError(t, db.Put(wo, []byte("key2"), []byte("val2")))
+	require.NoError(t, db.Delete(wo, []byte("key3")))
+	require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+
+	expected := map[string]string{
+		"gorocksdb.test.puts":    "2",
+		"gorocksdb.test.deletes": "1",
+		"gorocksdb.test.cf":      "0",
+	}
+
+	props, err := db.GetPropertiesOfAllTables()
+	require.NoError(t, err)
+	require.Len(t, props, 1)
+	for _, p := range props {
+		require.Equal(t, uint64(3), p.NumEntries)
+		require.Equal(t, expected, p.UserCollectedProperties)
+	}
+
+	// the properties are keyed by the path of the live files.
+	liveFiles := db.GetLiveFilesMetaData()
+	require.Len(t, liveFiles, 1)
+	require.Nil(t, liveFiles[0].UserCollectedProperties)
+	p, ok := props[db.Name()+liveFiles[0].Name]
+	require.True(t, ok)
+	require.Equal(t, expected, p.UserCollectedProperties)
+
+	liveFiles, err = db.GetLiveFilesMetaDataWithProperties(nil)
+	require.NoError(t, err)
+	require.Len(t, liveFiles, 1)
+	require.Equal(t, expected, liveFiles[0].UserCollectedProperties)
+}
+
+func TestTablePropertiesCollectorColumnFamilies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestTablePropertiesCollectorColumnFamilies")
+	require.NoError(t, err)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissingColumnFamilies(true)
+	opts.SetCreateIfMissing(true)
+	opts.AddTablePropertiesCollectorFactory(&mockTablePropertiesCollectorFactory{})
+	db, cfh, err := OpenDbColumnFamilies(opts, dir, []string{"default", "guide"}, []*Options{opts, opts})
+	require.NoError(t, err)
+	defer db.Close()
+	defer cfh[0].Destroy()
+	defer cfh[1].Destroy()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key1"), []byte("val1")))
+	require.NoError(t, db.Flush(NewDefaultFlushOptions()))
+	require.NoError(t, db.PutCF(wo, cfh[1], []byte("key1"), []byte("val1")))
+	require.NoError(t, db.PutCF(wo, cfh[1], []byte("key2"), []byte("val2")))
+	// the manual compaction flushes the memtable of the column family.
+	db.CompactRangeCF(cfh[1], Range{})
+
+	// the file of guide has no properties without its handle.
+	_, err = db.GetLiveFilesMetaDataWithProperties(nil)
+	require.Error(t, err)
+
+	liveFiles, err := db.GetLiveFilesMetaDataWithProperties(cfh)
+	require.NoError(t, err)
+	require.Len(t, liveFiles, 2)
+	for _, lf := range liveFiles {
+		switch lf.ColumnFamilyName {
+		case "default":
+			require.Equal(t, "1", lf.UserCollectedProperties["gorocksdb.test.puts"])
+			require.Equal(t, "0", lf.UserCollectedProperties["gorocksdb.test.cf"])
+		case "guide":
+			require.Equal(t, "2", lf.UserCollectedProperties["gorocksdb.test.puts"])
+			require.Equal(t, "1", lf.UserCollectedProperties["gorocksdb.test.cf"])
+		default:
+			t.Fatalf("unexpected column family %s", lf.ColumnFamilyName)
+		}
+	}
+}
+
+type mockTablePropertiesCollectorFactory struct{}
+
+func (f *mockTablePropertiesCollectorFactory) Name() string { return "gorocksdb.test" }
+func (f *mockTablePropertiesCollectorFactory) CreateTablePropertiesCollector(columnFamilyID uint32) TablePropertiesCollector {
+	return &mockTablePropertiesCollector{columnFamilyID: columnFamilyID}
+}
+
+type mockTablePropertiesCollector struct {
+	columnFamilyID uint32
+	puts, deletes  int
+}
+
+func (c *mockTablePropertiesCollector) Name() string { return "gorocksdb.test" }
+func (c *mockTablePropertiesCollector) AddUserKey(key, value []byte, entryType EntryType, seq uint64, fileSize uint64) error {
+	switch entryType {
+	case EntryPut:
+		c.puts++
+	case EntryDelete:
+		c.deletes++
+	}
+	return nil
+}
+func (c *mockTablePropertiesCollector) Finish() (map[string]string, error) {
+	return map[string]string{
+		"gorocksdb.test.puts":    strconv.Itoa(c.puts),
+		"gorocksdb.test.deletes": strconv.Itoa(c.deletes),
+		"gorocksdb.test.cf":      strconv.Itoa(int(c.columnFamilyID)),
+	}, nil
+}
@@ -0,0 +1,109 @@
+#include "table_properties_extension.h"
+
+#include <string>
+#include <utility>
+#include <vector>
+#include "rocksdb_internal.h"
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::TableProperties;
+using rocksdb::TablePropertiesCollection;
+using rocksdb::UserCollectedProperties;
+
+struct gorocksdb_user_collected_properties_t {
+	UserCollectedProperties rep;
+};
+
+struct gorocksdb_tableproperties_collection_t {
+	std::vector<std::pair<std::string, std::shared_ptr<const TableProperties>>> rep;
+};
+
+static void set_string(const std::string& src, char** dst, size_t* dst_len) {
+	*dst = const_cast<char*>(src.data());
+	*dst_len = src.size();
+}
+
+void gorocksdb_set_tableproperties(gorocksdb_tableproperties_t* dst, const TableProperties& src) {
+	dst->data_size = src.data_size;
+	dst->index_size = src.index_size;
+	dst->filter_size = src.filter_size;
+	dst->raw_key_size = src.raw_key_size;
+	dst->raw_value_size = src.raw_value_size;
+	dst->num_data_blocks = src.num_data_blocks;
+	dst->num_entries = src.num_entries;
+	dst->num_deletions = src.num_deletions;
+	dst->num_merge_operands = src.num_merge_operands;
+	dst->num_range_deletions = src.num_range_deletions;
+	dst->format_version = src.format_version;
+	dst->creation_time = src.creation_time;
+	dst->oldest_key_time = src.oldest_key_time;
+	dst->file_creation_time = src.file_creation_time;
+	set_string(src.column_family_name, &dst->column_family_name, &dst->column_family_name_len);
+	set_string(src.comparator_name, &dst->comparator_name, &dst->comparator_name_len);
+	set_string(src.merge_operator_name, &dst->merge_operator_name, &dst->merge_operator_name_len);
+	set_string(src.prefix_extractor_name, &dst->prefix_extractor_name, &dst->prefix_extractor_name_len);
+	set_string(src.compression_name, &dst->compression_name, &dst->compression_name_len);
+	dst->user_collected_properties = reinterpret_cast<const gorocksdb_user_collected_properties_t*>(
+		&src.user_collected_properties);
+}
+
+extern "C" {
+
+
+void gorocksdb_tableproperties_ref_destroy(gorocksdb_tableproperties_ref_t* ref) {
+	delete ref;
+}
+
+size_t gorocksdb_user_collected_properties_count(const gorocksdb_user_collected_properties_t* props) {
+	return props->rep.size();
+}
+
+void gorocksdb_user_collected_properties_get(
+	const gorocksdb_user_collected_properties_t* props,
+	char** keys, size_t* keys_lens, char** values, size_t* values_lens) {
+
+	size_t i = 0;
+	for (const auto& kv : props->rep) {
+		set_string(kv.first, &keys[i], &keys_lens[i]);
+		set_string(kv.second, &values[i], &values_lens[i]);
+		i++;
+	}
+}
+
+void gorocksdb_user_collected_properties_add(
+	gorocksdb_user_collected_properties_t* props,
+	const char* key, size_t key_len, const char* value, size_t value_len) {
+
+	props->rep[std::string(key, key_len)] = std::string(value, value_len);
+}
+
+gorocksdb_tableproperties_collection_t* gorocksdb_get_properties_of_all_tables(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family, size_t* num, char** errptr) {
+
+	*num = 0;
+	ColumnFamilyHandle* cf = column_family == NULL ? db->rep->DefaultColumnFamily() : column_family->rep;
+	TablePropertiesCollection props;
+	if (SaveError(errptr, db->rep->GetPropertiesOfAllTables(cf, &props))) {
+		return NULL;
+	}
+	gorocksdb_tableproperties_collection_t* collection = new gorocksdb_tableproperties_collection_t;
+	collection->rep.assign(props.begin(), props.end());
+	*num = collection->rep.size();
+	return collection;
+}
+
+void gorocksdb_tableproperties_collection_get(
+	const gorocksdb_tableproperties_collection_t* collection, size_t index,
+	char** path, size_t* path_len, gorocksdb_tableproperties_t* props) {
+
+	const auto& entry = collection->rep[index];
+	set_string(entry.first, path, path_len);
+	gorocksdb_set_tableproperties(props, *entry.second);
+}
+
+void gorocksdb_tableproperties_collection_destroy(gorocksdb_tableproperties_collection_t* collection) {
+	delete collection;
+}
+
+
+}
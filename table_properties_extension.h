@@ -0,0 +1,90 @@
+#ifndef GOROCKSDB_TABLE_PROPERTIES_EXTENSION_H
+#define GOROCKSDB_TABLE_PROPERTIES_EXTENSION_H
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+#include <stdint.h>
+#include <stdlib.h>
+#include "rocksdb/c.h"
+
+/* Table properties */
+
+// A rocksdb::UserCollectedProperties map.
+typedef struct gorocksdb_user_collected_properties_t gorocksdb_user_collected_properties_t;
+
+typedef struct {
+	uint64_t data_size;
+	uint64_t index_size;
+	uint64_t filter_size;
+	uint64_t raw_key_size;
+	uint64_t raw_value_size;
+	uint64_t num_data_blocks;
+	uint64_t num_entries;
+	uint64_t num_deletions;
+	uint64_t num_merge_operands;
+	uint64_t num_range_deletions;
+	uint64_t format_version;
+	uint64_t creation_time;
+	uint64_t oldest_key_time;
+	uint64_t file_creation_time;
+	char* column_family_name;
+	size_t column_family_name_len;
+	char* comparator_name;
+	size_t comparator_name_len;
+	char* merge_operator_name;
+	size_t merge_operator_name_len;
+	char* prefix_extractor_name;
+	size_t prefix_extractor_name_len;
+	char* compression_name;
+	size_t compression_name_len;
+	const gorocksdb_user_collected_properties_t* user_collected_properties;
+} gorocksdb_tableproperties_t;
+
+// Holds a reference to the table properties the strings of a
+// gorocksdb_tableproperties_t point into.
+typedef struct gorocksdb_tableproperties_ref_t gorocksdb_tableproperties_ref_t;
+
+void gorocksdb_tableproperties_ref_destroy(gorocksdb_tableproperties_ref_t* ref);
+
+size_t gorocksdb_user_collected_properties_count(const gorocksdb_user_collected_properties_t* props);
+
+// Sets the arrays of length gorocksdb_user_collected_properties_count
+// to the keys and values, which point into props.
+void gorocksdb_user_collected_properties_get(
+	const gorocksdb_user_collected_properties_t* props,
+	char** keys, size_t* keys_lens, char** values, size_t* values_lens);
+
+void gorocksdb_user_collected_properties_add(
+	gorocksdb_user_collected_properties_t* props,
+	const char* key, size_t key_len, const char* value, size_t value_len);
+
+// Maps the paths of table files to their properties.
+typedef struct gorocksdb_tableproperties_collection_t gorocksdb_tableproperties_collection_t;
+
+// column_family may be NULL for the default column family.
+gorocksdb_tableproperties_collection_t* gorocksdb_get_properties_of_all_tables(
+	rocksdb_t* db, rocksdb_column_family_handle_t* column_family, size_t* num, char** errptr);
+
+// The strings of props point into the collection.
+void gorocksdb_tableproperties_collection_get(
+	const gorocksdb_tableproperties_collection_t* collection, size_t index,
+	char** path, size_t* path_len, gorocksdb_tableproperties_t* props);
+
+void gorocksdb_tableproperties_collection_destroy(gorocksdb_tableproperties_collection_t* collection);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+
+#include <memory>
+#include "rocksdb/table_properties.h"
+
+struct gorocksdb_tableproperties_ref_t {
+	std::shared_ptr<const rocksdb::TableProperties> rep;
+};
+
+// Sets dst to the properties of src, the strings point into src.
+void gorocksdb_set_tableproperties(gorocksdb_tableproperties_t* dst, const rocksdb::TableProperties& src);
+#endif
+
+#endif  // GOROCKSDB_TABLE_PROPERTIES_EXTENSION_H
@@ -2,6 +2,7 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "db_extension.h"
 import "C"
 import (
 	"errors"
@@ -14,6 +15,12 @@ type TransactionDB struct {
 	name              string
 	opts              *Options
 	transactionDBOpts *TransactionDBOptions
+
+	// base is the handle as plain db for backups,
+	// it is created on open and freed in Close.
+	base *C.rocksdb_t
+	// listeners holds the per DB state of the listeners of opts.
+	listeners dbListeners
 }
 
 // OpenTransactionDb opens a database with the specified options.
@@ -27,18 +34,89 @@ func OpenTransactionDb(
 		cName = C.CString(name)
 	)
 	defer C.free(unsafe.Pointer(cName))
-	db := C.rocksdb_transactiondb_open(
-		opts.c, transactionDBOpts.c, cName, &cErr)
+	db := &TransactionDB{
+		name:              name,
+		opts:              opts,
+		transactionDBOpts: transactionDBOpts,
+	}
+	cDBOpts, freeDBOpts := db.listeners.attach(opts)
+	defer freeDBOpts()
+	db.c = C.rocksdb_transactiondb_open(
+		cDBOpts, transactionDBOpts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
+		db.listeners.detach()
 		return nil, errors.New(C.GoString(cErr))
 	}
-	return &TransactionDB{
+	db.base = C.gorocksdb_transactiondb_get_base_db(db.c)
+	return db, nil
+}
+
+// OpenTransactionDbColumnFamilies opens a database with the specified column families.
+func OpenTransactionDbColumnFamilies(
+	opts *Options,
+	transactionDBOpts *TransactionDBOptions,
+	name string,
+	cfNames []string,
+	cfOpts []*Options,
+) (*TransactionDB, []*ColumnFamilyHandle, error) {
+	numColumnFamilies := len(cfNames)
+	if numColumnFamilies != len(cfOpts) {
+		return nil, nil, errors.New("must provide the same number of column family names and options")
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	cNames := make([]*C.char, numColumnFamilies)
+	for i, s := range cfNames {
+		cNames[i] = C.CString(s)
+	}
+	defer func() {
+		for _, s := range cNames {
+			C.free(unsafe.Pointer(s))
+		}
+	}()
+
+	cOpts := make([]*C.rocksdb_options_t, numColumnFamilies)
+	for i, o := range cfOpts {
+		cOpts[i] = o.c
+	}
+
+	cHandles := make([]*C.rocksdb_column_family_handle_t, numColumnFamilies)
+
+	db := &TransactionDB{
 		name:              name,
-		c:                 db,
 		opts:              opts,
 		transactionDBOpts: transactionDBOpts,
-	}, nil
+	}
+	cDBOpts, freeDBOpts := db.listeners.attach(opts)
+	defer freeDBOpts()
+
+	var cErr *C.char
+	db.c = C.rocksdb_transactiondb_open_column_families(
+		cDBOpts,
+		transactionDBOpts.c,
+		cName,
+		C.int(numColumnFamilies),
+		&cNames[0],
+		&cOpts[0],
+		&cHandles[0],
+		&cErr,
+	)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		db.listeners.detach()
+		return nil, nil, errors.New(C.GoString(cErr))
+	}
+	db.base = C.gorocksdb_transactiondb_get_base_db(db.c)
+
+	cfHandles := make([]*ColumnFamilyHandle, numColumnFamilies)
+	for i, c := range cHandles {
+		cfHandles[i] = NewNativeColumnFamilyHandle(c)
+	}
+
+	return db, cfHandles, nil
 }
 
 // NewSnapshot creates a new snapshot of the database.
@@ -89,6 +167,23 @@ func (db *TransactionDB) Get(opts *ReadOptions, key []byte) ([]byte, error) {
 	return charToByte(cValue, cValLen), nil
 }
 
+// GetCF returns the data associated with the key from the database and column family.
+func (db *TransactionDB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) ([]byte, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_transactiondb_get_cf(
+		db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, &cErr,
+	)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	return charToByte(cValue, cValLen), nil
+}
+
 // Put writes data associated with a key to the database.
 func (db *TransactionDB) Put(opts *WriteOptions, key, value []byte) error {
 	var (
@@ -106,6 +201,23 @@ func (db *TransactionDB) Put(opts *WriteOptions, key, value []byte) error {
 	return nil
 }
 
+// PutCF writes data associated with a key to the database and column family.
+func (db *TransactionDB) PutCF(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transactiondb_put_cf(
+		db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr,
+	)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
 // Delete removes the data associated with the key from the database.
 func (db *TransactionDB) Delete(opts *WriteOptions, key []byte) error {
 	var (
@@ -136,8 +248,16 @@ func (db *TransactionDB) NewCheckpoint() (*Checkpoint, error) {
 	return NewNativeCheckpoint(cCheckpoint), nil
 }
 
+// baseDB implements BackupableDB.
+func (db *TransactionDB) baseDB() *C.rocksdb_t {
+	return db.base
+}
+
 // Close closes the database.
 func (db *TransactionDB) Close() {
+	C.gorocksdb_transactiondb_base_db_destroy(db.base)
+	db.base = nil
 	C.rocksdb_transactiondb_close(db.c)
 	db.c = nil
+	db.listeners.detach()
 }
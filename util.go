@@ -60,6 +60,11 @@ func cByteSlice(b []byte) *C.char {
 	return c
 }
 
+// charToString copies a C string with length to a Go string.
+func charToString(data *C.char, len C.size_t) string {
+	return string(charToByte(data, len))
+}
+
 // stringToChar returns *C.char from string.
 func stringToChar(s string) *C.char {
 	ptrStr := (*reflect.StringHeader)(unsafe.Pointer(&s))
@@ -74,6 +79,20 @@ func charSlice(data **C.char, len C.int) []*C.char {
 	return value
 }
 
+// charsToStrings copies a C array of strings with lengths to a []string.
+func charsToStrings(data **C.char, lens *C.size_t, num C.size_t) []string {
+	if num == 0 {
+		return nil
+	}
+	cStrs := charSlice(data, C.int(num))
+	cLens := sizeSlice(lens, C.int(num))
+	strs := make([]string, num)
+	for i := range strs {
+		strs[i] = charToString(cStrs[i], cLens[i])
+	}
+	return strs
+}
+
 // sizeSlice converts a C array of size_t to a []C.size_t.
 func sizeSlice(data *C.size_t, len C.int) []C.size_t {
 	var value []C.size_t
@@ -0,0 +1,123 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "db_extension.h"
+import "C"
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+// WalFile describes a write ahead log file of a DB.
+// The log files are kept in the archive after they are obsolete
+// if SetWALTtlSeconds or SetWalSizeLimitMb is set.
+type WalFile struct {
+	// PathName is the name of the file relative to the wal dir,
+	// for example /000003.log or /archive/000003.log.
+	PathName  string
+	LogNumber uint64
+	// Alive is false if the file is in the archive.
+	Alive bool
+	// StartSequence is the sequence number of the first write in the file.
+	StartSequence uint64
+	Size          uint64
+	// ModificationTime is the time of the last write to the file,
+	// it is zero if the file was deleted meanwhile.
+	ModificationTime time.Time
+}
+
+// GetLatestSequenceNumber returns the sequence number of the most recent write.
+func (db *DB) GetLatestSequenceNumber() uint64 {
+	return uint64(C.rocksdb_get_latest_sequence_number(db.c))
+}
+
+// GetSortedWalFiles returns the alive and archived write ahead log files,
+// sorted by their log number.
+func (db *DB) GetSortedWalFiles() ([]WalFile, error) {
+	var (
+		cErr *C.char
+		cNum C.size_t
+	)
+	cFiles := C.gorocksdb_get_sorted_wal_files(db.c, &cNum, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_wal_files_destroy(cFiles, cNum)
+
+	num := int(cNum)
+	files := make([]WalFile, num)
+	if num == 0 {
+		return files, nil
+	}
+	cFileSlice := (*[1 << 30]C.gorocksdb_wal_file_t)(unsafe.Pointer(cFiles))[:num:num]
+	for i, cFile := range cFileSlice {
+		files[i] = WalFile{
+			PathName:      charToString(cFile.path_name, cFile.path_name_len),
+			LogNumber:     uint64(cFile.log_number),
+			Alive:         cFile.alive != 0,
+			StartSequence: uint64(cFile.start_sequence),
+			Size:          uint64(cFile.size_file_bytes),
+		}
+		if cFile.modification_time != 0 {
+			files[i].ModificationTime = time.Unix(int64(cFile.modification_time), 0)
+		}
+	}
+	return files, nil
+}
+
+// WalIterator iterates over the write batches in the write ahead log files.
+type WalIterator struct {
+	c *C.rocksdb_wal_iterator_t
+}
+
+// GetUpdatesSince returns an iterator over the write batches since the
+// sequence number seq. The first batch contains seq but may start before it.
+// It returns an error if the log files containing seq were deleted.
+func (db *DB) GetUpdatesSince(seq uint64) (*WalIterator, error) {
+	var cErr *C.char
+	cIter := C.rocksdb_get_updates_since(db.c, C.uint64_t(seq), nil, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	return &WalIterator{c: cIter}, nil
+}
+
+// Valid returns false only when the iterator is past the last batch
+// or an error occurred.
+func (iter *WalIterator) Valid() bool {
+	return C.rocksdb_wal_iter_valid(iter.c) != 0
+}
+
+// Next moves the iterator to the next batch.
+func (iter *WalIterator) Next() {
+	C.rocksdb_wal_iter_next(iter.c)
+}
+
+// GetBatch returns the current batch and the sequence number of its first write.
+// The batch has to be destroyed.
+func (iter *WalIterator) GetBatch() (*WriteBatch, uint64) {
+	var cSeq C.uint64_t
+	cBatch := C.rocksdb_wal_iter_get_batch(iter.c, &cSeq)
+	return NewNativeWriteBatch(cBatch), uint64(cSeq)
+}
+
+// Err returns the error of the iteration, if any.
+func (iter *WalIterator) Err() error {
+	var cErr *C.char
+	C.rocksdb_wal_iter_status(iter.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Destroy deallocates the WalIterator.
+func (iter *WalIterator) Destroy() {
+	C.rocksdb_wal_iter_destroy(iter.c)
+	iter.c = nil
+}
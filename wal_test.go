@@ -0,0 +1,44 @@
+package gorocksdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetUpdatesSince(t *testing.T) {
+	db := newTestDB(t, "TestGetUpdatesSince", func(opts *Options) {
+		opts.SetWALTtlSeconds(3600)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	require.NoError(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.Put([]byte("key2"), []byte("value2"))
+	wb.Delete([]byte("key1"))
+	require.NoError(t, db.Write(wo, wb))
+	require.Equal(t, uint64(3), db.GetLatestSequenceNumber())
+
+	files, err := db.GetSortedWalFiles()
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	require.True(t, files[len(files)-1].Alive)
+	require.False(t, files[len(files)-1].ModificationTime.IsZero())
+
+	iter, err := db.GetUpdatesSince(1)
+	require.NoError(t, err)
+	defer iter.Destroy()
+
+	var seqs []uint64
+	var counts []int
+	for ; iter.Valid(); iter.Next() {
+		batch, seq := iter.GetBatch()
+		seqs = append(seqs, seq)
+		counts = append(counts, batch.Count())
+		batch.Destroy()
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, []uint64{1, 2}, seqs)
+	require.Equal(t, []int{1, 2}, counts)
+}